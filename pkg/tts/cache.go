@@ -0,0 +1,102 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package tts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cache stores synthesized audio keyed by a digest of the inputs that
+// produced it, so repeated requests (common Alfred acknowledgements like
+// "yes" or "one moment") skip the TTS engine entirely.
+type Cache interface {
+	// Get returns the cached audio for key, if present.
+	Get(key string) ([]byte, bool)
+	// Set stores audio under key.
+	Set(key string, data []byte)
+}
+
+// CacheKeyInput is the set of inputs that fully determine synthesized
+// audio; two requests with an identical CacheKeyInput produce identical
+// output.
+type CacheKeyInput struct {
+	Text        string
+	SSML        bool
+	Voice       string
+	LengthScale float64
+	SpeakerID   int
+	NoiseScale  float64
+	NoiseW      float64
+}
+
+// CacheKey returns the SHA-256 hex digest identifying in's audio output.
+func CacheKey(in CacheKeyInput) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s|%v|%s|%g|%d|%g|%g", in.Text, in.SSML, in.Voice, in.LengthScale, in.SpeakerID, in.NoiseScale, in.NoiseW)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// MemoryCache is an unbounded in-process Cache. It's lost on restart, which
+// is fine for a single long-lived server process synthesizing a small,
+// repetitive set of phrases.
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string][]byte
+}
+
+// NewMemoryCache returns an empty MemoryCache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string][]byte)}
+}
+
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	data, ok := c.entries[key]
+	return data, ok
+}
+
+func (c *MemoryCache) Set(key string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = data
+}
+
+// DiskCache persists audio as one file per key under Dir, so the cache
+// survives process restarts.
+type DiskCache struct {
+	Dir string
+}
+
+// NewDiskCache returns a DiskCache rooted at dir, creating it if necessary.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create TTS cache dir: %w", err)
+	}
+	return &DiskCache{Dir: dir}, nil
+}
+
+func (c *DiskCache) path(key string) string {
+	return filepath.Join(c.Dir, key+".pcm")
+}
+
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+func (c *DiskCache) Set(key string, data []byte) {
+	if err := os.WriteFile(c.path(key), data, 0o644); err != nil {
+		log.Printf("[TTS] failed to write cache entry %s: %v", key, err)
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package tts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// chunkedSynthesizeThreshold is the input length, in characters, above
+// which SynthesizeHandler switches from a single Synthesize call to
+// ChunkedSynthesize. Most cloud TTS engines cap a single request around
+// 5000 characters; Alfred's LLM replies can exceed that.
+const chunkedSynthesizeThreshold = 3000
+
+// defaultChunkMaxChars bounds how much text a single chunk carries.
+const defaultChunkMaxChars = 1000
+
+// defaultChunkParallelism caps how many chunks synthesize concurrently. For
+// the non-SSML path this also caps how many extra persistent piper workers
+// ChunkedSynthesize spins up per voice (see getWorkerSlot), so raising it
+// trades idle piper processes for latency on long replies.
+const defaultChunkParallelism = 3
+
+// ChunkedSynthesizeOptions customizes ChunkedSynthesize.
+type ChunkedSynthesizeOptions struct {
+	SynthesizeOptions
+	MaxChunkChars int    // max chars per chunk (an SSML element longer than this stays whole); 0 uses a sane default
+	Parallelism   int    // max concurrent chunk synthesis calls; 0 uses a sane default
+	Format        string // "mp3" or "opus" to encode the stitched result once; empty returns raw PCM
+}
+
+// ChunkedSynthesize splits text into paragraph/sentence-bounded chunks
+// (preserving SSML tag integrity when opts.SSML is set), synthesizes them
+// concurrently via an errgroup bounded by opts.Parallelism, and stitches
+// the resulting PCM buffers back together in order.
+//
+// For opts.SSML, each chunk already runs piper as a one-off process
+// (synthesizeSSML), so chunks genuinely run in parallel. For plain text,
+// chunks fan out across a per-voice pool of up to opts.Parallelism
+// persistent piper workers (one piper process per pool slot) instead of
+// all sharing the single worker a non-chunked Synthesize call would use,
+// so one long reply doesn't serialize behind one worker's mutex.
+//
+// Chunks are always stitched as raw PCM, then optionally encoded once as a
+// whole via opts.Format. Encoding per-chunk and merging the encoded output
+// afterwards would require skipping duplicate ID3/Ogg headers and
+// realigning MPEG bit-reservoir state across chunk boundaries; encoding the
+// full concatenated PCM stream in a single ffmpeg pass avoids that class of
+// bug entirely while still producing one continuous, correctly timed file.
+func (s *TTSService) ChunkedSynthesize(ctx context.Context, text string, opts ChunkedSynthesizeOptions) ([]byte, error) {
+	maxChars := opts.MaxChunkChars
+	if maxChars <= 0 {
+		maxChars = defaultChunkMaxChars
+	}
+	parallelism := opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = defaultChunkParallelism
+	}
+
+	var chunks []string
+	if opts.SSML {
+		chunks = splitSSMLChunks(text, maxChars)
+	} else {
+		chunks = splitTextChunks(text, maxChars)
+	}
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	synthOpts := s.normalizeOptions(opts.SynthesizeOptions)
+
+	results := make([][]byte, len(chunks))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(parallelism)
+	for i, chunk := range chunks {
+		i, chunk := i, chunk
+		g.Go(func() error {
+			var pcm []byte
+			var err error
+			if synthOpts.SSML {
+				pcm, err = s.synthesizeSSMLPCM(gctx, chunk, synthOpts)
+			} else {
+				pcm, err = s.synthesizeSlotPCM(gctx, chunk, synthOpts, i%parallelism)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to synthesize chunk %d/%d: %w", i+1, len(chunks), err)
+			}
+			results[i] = pcm
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var pcm []byte
+	for _, chunk := range results {
+		pcm = append(pcm, chunk...)
+	}
+
+	if opts.Format != "mp3" && opts.Format != "opus" {
+		return pcm, nil
+	}
+
+	var buf bytes.Buffer
+	encoderIn, encoderDone, err := startStreamEncoder(ctx, opts.Format, s.config.SampleRate, &buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := encoderIn.Write(pcm); err != nil {
+		encoderIn.Close()
+		<-encoderDone
+		return nil, fmt.Errorf("failed to write stitched pcm to encoder: %w", err)
+	}
+	encoderIn.Close()
+	if err := <-encoderDone; err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// sentenceSplitRe matches a run of text up to and including its terminating
+// punctuation, so splitSentences can break paragraphs without cutting a
+// sentence in half.
+var sentenceSplitRe = regexp.MustCompile(`(?s)[^.!?]*[.!?]+(?:\s+|$)`)
+
+// splitSentences splits a paragraph into sentences, falling back to the
+// whole paragraph if no terminal punctuation is found.
+func splitSentences(text string) []string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+
+	matches := sentenceSplitRe.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return []string{text}
+	}
+
+	var out []string
+	consumed := 0
+	for _, m := range matches {
+		if trimmed := strings.TrimSpace(text[consumed:m[1]]); trimmed != "" {
+			out = append(out, trimmed)
+		}
+		consumed = m[1]
+	}
+	if rest := strings.TrimSpace(text[consumed:]); rest != "" {
+		out = append(out, rest)
+	}
+	return out
+}
+
+// splitTextChunks splits plain text into chunks no longer than maxChars,
+// breaking on paragraph boundaries and then sentence boundaries so a chunk
+// only ends mid-sentence when a single sentence alone exceeds maxChars.
+func splitTextChunks(text string, maxChars int) []string {
+	var units []string
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		for _, sentence := range splitSentences(paragraph) {
+			for len(sentence) > maxChars {
+				cut := strings.LastIndexByte(sentence[:maxChars], ' ')
+				if cut <= 0 {
+					cut = maxChars
+					for cut > 1 && !utf8.RuneStart(sentence[cut]) {
+						cut--
+					}
+				}
+				units = append(units, strings.TrimSpace(sentence[:cut]))
+				sentence = strings.TrimSpace(sentence[cut:])
+			}
+			units = append(units, sentence)
+		}
+	}
+	return packChunks(units, maxChars)
+}
+
+// packChunks greedily packs units (sentences, or top-level SSML elements)
+// into chunks no longer than maxChars, joined by a single space. A unit that
+// is itself longer than maxChars becomes a chunk of its own.
+func packChunks(units []string, maxChars int) []string {
+	var chunks []string
+	var current strings.Builder
+	for _, u := range units {
+		if u == "" {
+			continue
+		}
+		if current.Len() > 0 && current.Len()+1+len(u) > maxChars {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+		if current.Len() > 0 {
+			current.WriteString(" ")
+		}
+		current.WriteString(u)
+	}
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+	return chunks
+}
+
+// speakRootRe strips an SSML document's outer <speak> element so its
+// children can be chunked and each chunk re-wrapped in its own root.
+var speakRootRe = regexp.MustCompile(`(?s)^\s*<speak[^>]*>(.*)</speak>\s*$`)
+
+// splitSSMLChunks splits an SSML document into chunks of complete top-level
+// elements, each re-wrapped in its own <speak> root so every chunk remains
+// independently valid SSML.
+func splitSSMLChunks(ssml string, maxChars int) []string {
+	inner := ssml
+	if m := speakRootRe.FindStringSubmatch(ssml); len(m) == 2 {
+		inner = m[1]
+	}
+
+	bodies := packChunks(splitTopLevelSSML(inner), maxChars)
+	chunks := make([]string, len(bodies))
+	for i, body := range bodies {
+		chunks[i] = "<speak>" + body + "</speak>"
+	}
+	return chunks
+}
+
+// splitTopLevelSSML splits SSML markup into top-level units -- a run of
+// text, or one complete element including its children -- without ever
+// cutting inside a tag, so a chunk boundary can never separate an opening
+// tag from its matching close.
+func splitTopLevelSSML(ssml string) []string {
+	var units []string
+	depth := 0
+	start := 0
+
+	for i := 0; i < len(ssml); {
+		if ssml[i] != '<' {
+			i++
+			continue
+		}
+		tagEnd := strings.IndexByte(ssml[i:], '>')
+		if tagEnd < 0 {
+			break
+		}
+		tagEnd += i
+		tag := ssml[i : tagEnd+1]
+
+		switch {
+		case strings.HasPrefix(tag, "</"):
+			depth--
+			if depth == 0 {
+				units = append(units, strings.TrimSpace(ssml[start:tagEnd+1]))
+				start = tagEnd + 1
+			}
+		case strings.HasSuffix(tag, "/>"):
+			if depth == 0 {
+				units = append(units, strings.TrimSpace(ssml[start:tagEnd+1]))
+				start = tagEnd + 1
+			}
+		default:
+			if depth == 0 {
+				if text := strings.TrimSpace(ssml[start:i]); text != "" {
+					units = append(units, text)
+				}
+				start = i
+			}
+			depth++
+		}
+		i = tagEnd + 1
+	}
+	if rest := strings.TrimSpace(ssml[start:]); rest != "" {
+		units = append(units, rest)
+	}
+	return units
+}
@@ -3,12 +3,23 @@
 
 package tts
 
+// VoiceConfig describes a single voice available to the TTS service.
+type VoiceConfig struct {
+	ModelPath   string  `json:"modelPath"`            // Path to voice model .onnx file
+	ConfigPath  string  `json:"configPath,omitempty"` // Path to piper's .onnx.json config, if not alongside the model
+	LengthScale float64 `json:"lengthScale"`          // Default speech rate (1.0 = normal, >1.0 = slower, <1.0 = faster)
+	Language    string  `json:"language,omitempty"`   // BCP-47 language tag, e.g. "en-GB"
+	SpeakerID   int     `json:"speakerId,omitempty"`  // Speaker id for multi-speaker onnx models
+	Gender      string  `json:"gender,omitempty"`     // "male", "female", or "neutral", for UI display only
+}
+
 // Config holds TTS configuration
 type Config struct {
-	PiperPath   string  `json:"piperPath"`   // Path to piper executable
-	ModelPath   string  `json:"modelPath"`   // Path to voice model .onnx file
-	SampleRate  int     `json:"sampleRate"`  // Output sample rate
-	LengthScale float64 `json:"lengthScale"` // Speech rate (1.0 = normal, >1.0 = slower, <1.0 = faster)
+	PiperPath    string                 `json:"piperPath"`          // Path to piper executable
+	Voices       map[string]VoiceConfig `json:"voices"`             // Available voices, keyed by voice name
+	DefaultVoice string                 `json:"defaultVoice"`       // Key into Voices used when a request doesn't specify one
+	SampleRate   int                    `json:"sampleRate"`         // Output sample rate
+	CacheDir     string                 `json:"cacheDir,omitempty"` // Persist synthesized audio here, keyed by content hash; empty uses an in-memory cache instead
 }
 
 // DefaultConfig returns default TTS configuration
@@ -16,9 +27,16 @@ func DefaultConfig() *Config {
 	// For dev: use absolute paths
 	// For prod: these will be overridden by electron-builder bundled resources
 	return &Config{
-		PiperPath:   "/Users/zakicole/alfred/bin/piper",
-		ModelPath:   "/Users/zakicole/alfred/models/tts/en_GB-northern_english_male-medium.onnx",
-		SampleRate:  22050,
-		LengthScale: 1.15, // Slightly slower than default (1.0)
+		PiperPath: "/Users/zakicole/alfred/bin/piper",
+		Voices: map[string]VoiceConfig{
+			"northern-english-male": {
+				ModelPath:   "/Users/zakicole/alfred/models/tts/en_GB-northern_english_male-medium.onnx",
+				LengthScale: 1.15, // Slightly slower than default (1.0)
+				Language:    "en-GB",
+				Gender:      "male",
+			},
+		},
+		DefaultVoice: "northern-english-male",
+		SampleRate:   22050,
 	}
 }
@@ -0,0 +1,130 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package tts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os/exec"
+)
+
+// Encoder converts raw 16-bit mono PCM samples at the given sample rate
+// into an encoded audio format, returning the encoded bytes and MIME type.
+// Implementations are looked up by name via encoderForFormat.
+type Encoder interface {
+	Encode(pcm []int16, sr int) ([]byte, string, error)
+}
+
+// encoderForFormat resolves a format name (from a "format=" query parameter
+// or a negotiated Accept header) to its Encoder.
+func encoderForFormat(format string) (Encoder, bool) {
+	switch format {
+	case "wav":
+		return WAVEncoder{}, true
+	case "mp3", "mpeg":
+		return MP3Encoder{}, true
+	case "opus", "ogg":
+		return OpusEncoder{}, true
+	case "pcm", "":
+		return PCMEncoder{}, true
+	default:
+		return nil, false
+	}
+}
+
+// formatFileExt returns the file extension SynthesizeHandler should
+// advertise via Content-Disposition for a given format name.
+func formatFileExt(format string) string {
+	switch format {
+	case "opus", "ogg":
+		return "ogg"
+	case "":
+		return "pcm"
+	default:
+		return format
+	}
+}
+
+// pcmBytesToInt16 reinterprets little-endian s16le bytes as samples.
+func pcmBytesToInt16(data []byte) []int16 {
+	samples := make([]int16, len(data)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(data[i*2:]))
+	}
+	return samples
+}
+
+// int16ToPCMBytes serializes samples back to little-endian s16le bytes.
+func int16ToPCMBytes(samples []int16) []byte {
+	buf := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(buf[i*2:], uint16(s))
+	}
+	return buf
+}
+
+// PCMEncoder returns raw PCM unchanged.
+type PCMEncoder struct{}
+
+func (PCMEncoder) Encode(pcm []int16, sr int) ([]byte, string, error) {
+	return int16ToPCMBytes(pcm), "audio/pcm", nil
+}
+
+// WAVEncoder wraps raw PCM in a WAV RIFF header.
+type WAVEncoder struct{}
+
+func (WAVEncoder) Encode(pcm []int16, sr int) ([]byte, string, error) {
+	data := int16ToPCMBytes(pcm)
+	var buf bytes.Buffer
+	writeWAVHeader(&buf, len(data), sr)
+	buf.Write(data)
+	return buf.Bytes(), "audio/wav", nil
+}
+
+// MP3Encoder pipes PCM through ffmpeg to produce MP3.
+type MP3Encoder struct{}
+
+func (MP3Encoder) Encode(pcm []int16, sr int) ([]byte, string, error) {
+	data, err := encodeToMP3(int16ToPCMBytes(pcm), sr)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, "audio/mpeg", nil
+}
+
+// OpusEncoder pipes PCM through ffmpeg to produce an Ogg/Opus stream.
+type OpusEncoder struct{}
+
+func (OpusEncoder) Encode(pcm []int16, sr int) ([]byte, string, error) {
+	data, err := encodeToOpus(int16ToPCMBytes(pcm), sr)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, "audio/ogg; codecs=opus", nil
+}
+
+// encodeToOpus pipes raw 16-bit mono PCM through ffmpeg and returns an
+// Ogg/Opus stream.
+func encodeToOpus(pcm []byte, sampleRate int) ([]byte, error) {
+	cmd := exec.Command("ffmpeg",
+		"-f", "s16le",
+		"-ar", fmt.Sprintf("%d", sampleRate),
+		"-ac", "1",
+		"-i", "pipe:0",
+		"-f", "ogg",
+		"-c:a", "libopus",
+		"-b:a", "64k",
+		"pipe:1",
+	)
+	cmd.Stdin = bytes.NewReader(pcm)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg opus encode failed: %w, stderr: %s", err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
@@ -9,13 +9,20 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"time"
 )
 
 // TTSRequest represents a text-to-speech request
 type TTSRequest struct {
-	Text         string `json:"text"`
-	ExtractVerbal bool   `json:"extractVerbal"` // If true, extract [[VERBAL]] tags
+	Text          string  `json:"text"`
+	ExtractVerbal bool    `json:"extractVerbal"`          // If true, extract [[VERBAL]] tags
+	Voice         string  `json:"voice,omitempty"`        // Voice key into Config.Voices; empty auto-selects via LanguageCode/Gender, then the default voice
+	SSML          bool    `json:"ssml,omitempty"`         // If true, Text is SSML markup (e.g. "<speak>...</speak>") instead of plain text
+	LanguageCode  string  `json:"languageCode,omitempty"` // BCP-47 tag (e.g. "en-GB") used to auto-select a voice when Voice is empty
+	SpeakingRate  float32 `json:"speakingRate,omitempty"` // 1.0 = normal rate; > 1.0 faster, < 1.0 slower
+	Pitch         float32 `json:"pitch,omitempty"`        // reserved: piper has no pitch control, so this is accepted but not yet applied
+	Gender        string  `json:"gender,omitempty"`       // "male", "female", or "neutral", used to auto-select a voice when Voice is empty
 }
 
 // SynthesizeHandler handles HTTP POST requests for TTS
@@ -45,33 +52,175 @@ func SynthesizeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("[TTS] Synthesizing text (length: %d chars)", len(req.Text))
+	log.Printf("[TTS] Synthesizing text (length: %d chars, ssml: %v)", len(req.Text), req.SSML)
 
-	// Extract verbal response if requested
+	// Extract verbal response if requested; SSML markup isn't plain
+	// conversational text, so extraction is skipped for SSML input.
 	textToSpeak := req.Text
-	if req.ExtractVerbal {
+	if req.ExtractVerbal && !req.SSML {
 		textToSpeak = ExtractVerbalResponse(req.Text)
 		log.Printf("[TTS] Extracted verbal: %s", textToSpeak)
 	}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	// Create context with timeout; chunked synthesis of long replies gets
+	// more headroom since it runs several synthesis calls.
+	timeout := 30 * time.Second
+	if len(textToSpeak) > chunkedSynthesizeThreshold {
+		timeout = 2 * time.Minute
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
 	defer cancel()
 
-	// Synthesize
-	log.Printf("[TTS] Starting synthesis for text: %s", textToSpeak)
-	audioData, err := service.Synthesize(ctx, textToSpeak)
+	opts := SynthesizeOptions{
+		Voice:        req.Voice,
+		SSML:         req.SSML,
+		LanguageCode: req.LanguageCode,
+		SpeakingRate: req.SpeakingRate,
+		Pitch:        req.Pitch,
+		Gender:       req.Gender,
+	}
+
+	if isStreamRequest(r) {
+		streamSynthesize(w, ctx, service, textToSpeak, opts)
+		return
+	}
+
+	// Resolve which concrete voice/rate this request maps to up front, so
+	// the cache key reflects the actual audio that will be produced rather
+	// than an empty Voice auto-selected differently across requests.
+	resolved := service.normalizeOptions(opts)
+	cacheKey := CacheKey(CacheKeyInput{
+		Text:        textToSpeak,
+		SSML:        req.SSML,
+		Voice:       resolved.Voice,
+		LengthScale: resolved.LengthScale,
+		SpeakerID:   resolved.SpeakerID,
+		NoiseScale:  resolved.NoiseScale,
+		NoiseW:      resolved.NoiseW,
+	})
+
+	cacheStatus := "miss"
+	audioData, ok := service.Cache().Get(cacheKey)
+	if ok {
+		cacheStatus = "hit"
+	} else {
+		var err error
+		if len(textToSpeak) > chunkedSynthesizeThreshold {
+			log.Printf("[TTS] Text exceeds %d chars, using chunked synthesis", chunkedSynthesizeThreshold)
+			audioData, err = service.ChunkedSynthesize(ctx, textToSpeak, ChunkedSynthesizeOptions{SynthesizeOptions: opts})
+		} else {
+			log.Printf("[TTS] Starting synthesis for text: %s", textToSpeak)
+			audioData, err = service.Synthesize(ctx, textToSpeak, opts)
+		}
+		if err != nil {
+			log.Printf("[TTS] ERROR: Synthesis failed: %v", err)
+			http.Error(w, fmt.Sprintf("Synthesis failed: %v", err), http.StatusInternalServerError)
+			return
+		}
+		service.Cache().Set(cacheKey, audioData)
+	}
+
+	log.Printf("[TTS] Synthesis complete (cache %s), audio size: %d bytes", cacheStatus, len(audioData))
+
+	// Encode to whatever format the client negotiated. audioData itself is
+	// always cached as raw PCM regardless of format, so the cache doesn't
+	// fragment across equivalent requests that only differ in output format.
+	format := negotiateFormat(r)
+	encoder, ok := encoderForFormat(format)
+	if !ok {
+		http.Error(w, fmt.Sprintf("unsupported format: %s", format), http.StatusBadRequest)
+		return
+	}
+	encoded, mimeType, err := encoder.Encode(pcmBytesToInt16(audioData), service.config.SampleRate)
 	if err != nil {
-		log.Printf("[TTS] ERROR: Synthesis failed: %v", err)
-		http.Error(w, fmt.Sprintf("Synthesis failed: %v", err), http.StatusInternalServerError)
+		log.Printf("[TTS] ERROR: Encoding to %s failed: %v", format, err)
+		http.Error(w, fmt.Sprintf("Encoding failed: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	log.Printf("[TTS] Synthesis complete, audio size: %d bytes", len(audioData))
+	w.Header().Set("Content-Type", mimeType)
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(encoded)))
+	w.Header().Set("X-TTS-Cache", cacheStatus)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="speech.%s"`, formatFileExt(format)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(encoded)
+}
+
+// negotiateFormat picks an output format from a "format=" query parameter
+// or, failing that, the Accept header; it defaults to raw PCM so existing
+// clients that don't negotiate keep getting today's behavior.
+func negotiateFormat(r *http.Request) string {
+	if f := r.URL.Query().Get("format"); f != "" {
+		return f
+	}
+
+	accept := r.Header.Get("Accept")
+	switch {
+	case strings.Contains(accept, "audio/wav"):
+		return "wav"
+	case strings.Contains(accept, "audio/mpeg"):
+		return "mp3"
+	case strings.Contains(accept, "opus"):
+		return "opus"
+	default:
+		return "pcm"
+	}
+}
+
+// RegisterRoutes registers the TTS service HTTP handlers
+func RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/tts/synthesize", SynthesizeHandler)
+	mux.HandleFunc("/api/tts/voices", ListVoicesHandler)
+	mux.HandleFunc("/tts/voices", ListVoicesHandler)
+	mux.HandleFunc("/tts/stream", StreamWebSocketHandler)
+	mux.HandleFunc("/tts/stream-tokens", StreamTokensHandler)
+	mux.HandleFunc("/v1/audio/speech", OpenAISpeechHandler)
+}
+
+// isStreamRequest reports whether the client asked for a streamed response,
+// either via "Accept: audio/pcm; codecs=stream" or "?stream=1".
+func isStreamRequest(r *http.Request) bool {
+	if r.URL.Query().Get("stream") == "1" {
+		return true
+	}
+	return strings.Contains(r.Header.Get("Accept"), "codecs=stream")
+}
+
+// streamSynthesize writes synthesized audio to w as each chunk becomes
+// ready, using chunked transfer encoding via http.Flusher, instead of
+// buffering the whole reply first. Piper only yields one complete file per
+// utterance rather than incremental audio frames, so a "frame" here is one
+// sentence-sized text chunk -- still enough for playback to start well
+// before a long reply finishes synthesizing. Bypasses the audio cache,
+// since streamed responses are consumed incrementally rather than as one
+// cacheable blob.
+func streamSynthesize(w http.ResponseWriter, ctx context.Context, service *TTSService, text string, opts SynthesizeOptions) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	var chunks []string
+	if opts.SSML {
+		chunks = splitSSMLChunks(text, streamChunkMaxChars)
+	} else {
+		chunks = splitTextChunks(text, streamChunkMaxChars)
+	}
 
-	// Return audio data as raw PCM
 	w.Header().Set("Content-Type", "audio/pcm")
-	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(audioData)))
 	w.WriteHeader(http.StatusOK)
-	w.Write(audioData)
+
+	for _, chunk := range chunks {
+		pcm, err := service.Synthesize(ctx, chunk, opts)
+		if err != nil {
+			log.Printf("[TTS] ERROR: streaming synthesis failed: %v", err)
+			return
+		}
+		if _, err := w.Write(pcm); err != nil {
+			log.Printf("[TTS] ERROR: streaming write failed: %v", err)
+			return
+		}
+		flusher.Flush()
+	}
 }
@@ -0,0 +1,133 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os/exec"
+	"time"
+)
+
+// openAISpeechRequest mirrors OpenAI's /v1/audio/speech request body.
+type openAISpeechRequest struct {
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	ResponseFormat string  `json:"response_format"`
+	Speed          float32 `json:"speed"`
+}
+
+// writeWAVHeader writes a 16-bit mono PCM RIFF/WAV header for dataLen bytes
+// of sample data at the given sample rate.
+func writeWAVHeader(w *bytes.Buffer, dataLen, sampleRate int) {
+	const bitsPerSample = 16
+	const channels = 1
+	byteRate := sampleRate * channels * bitsPerSample / 8
+	blockAlign := channels * bitsPerSample / 8
+
+	w.WriteString("RIFF")
+	binary.Write(w, binary.LittleEndian, uint32(36+dataLen))
+	w.WriteString("WAVE")
+	w.WriteString("fmt ")
+	binary.Write(w, binary.LittleEndian, uint32(16))
+	binary.Write(w, binary.LittleEndian, uint16(1)) // PCM
+	binary.Write(w, binary.LittleEndian, uint16(channels))
+	binary.Write(w, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(w, binary.LittleEndian, uint32(byteRate))
+	binary.Write(w, binary.LittleEndian, uint16(blockAlign))
+	binary.Write(w, binary.LittleEndian, uint16(bitsPerSample))
+	w.WriteString("data")
+	binary.Write(w, binary.LittleEndian, uint32(dataLen))
+}
+
+// encodeToMP3 pipes raw 16-bit mono PCM through ffmpeg and returns MP3 bytes.
+func encodeToMP3(pcm []byte, sampleRate int) ([]byte, error) {
+	cmd := exec.Command("ffmpeg",
+		"-f", "s16le",
+		"-ar", fmt.Sprintf("%d", sampleRate),
+		"-ac", "1",
+		"-i", "pipe:0",
+		"-f", "mp3",
+		"-b:a", "96k",
+		"pipe:1",
+	)
+	cmd.Stdin = bytes.NewReader(pcm)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg encode failed: %w, stderr: %s", err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
+
+// OpenAISpeechHandler implements an OpenAI-compatible /v1/audio/speech
+// endpoint on top of the piper-backed TTSService, so any OpenAI SDK client
+// can use Alfred as its speech backend.
+func OpenAISpeechHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	service := GetTTSService()
+	if service == nil {
+		log.Printf("[TTS] ERROR: Service not initialized")
+		http.Error(w, "TTS service not initialized", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req openAISpeechRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request", http.StatusBadRequest)
+		return
+	}
+	if req.Input == "" {
+		http.Error(w, "input is required", http.StatusBadRequest)
+		return
+	}
+
+	// OpenAI's "speed" is a playback-rate multiplier; piper's "length-scale"
+	// is an inverse duration multiplier, so we invert it.
+	opts := SynthesizeOptions{Voice: req.Voice}
+	if req.Speed > 0 {
+		opts.LengthScale = 1.0 / float64(req.Speed)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	log.Printf("[TTS] OpenAI-compatible speech request (voice=%q, format=%q, chars=%d)", req.Voice, req.ResponseFormat, len(req.Input))
+	pcm, err := service.Synthesize(ctx, req.Input, opts)
+	if err != nil {
+		log.Printf("[TTS] ERROR: Synthesis failed: %v", err)
+		http.Error(w, fmt.Sprintf("Synthesis failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	switch req.ResponseFormat {
+	case "wav":
+		var buf bytes.Buffer
+		writeWAVHeader(&buf, len(pcm), service.config.SampleRate)
+		buf.Write(pcm)
+		w.Header().Set("Content-Type", "audio/wav")
+		w.Write(buf.Bytes())
+	default: // "mp3" and every other response_format (including "opus" and "pcm") are served as MP3
+		mp3, err := encodeToMP3(pcm, service.config.SampleRate)
+		if err != nil {
+			log.Printf("[TTS] ERROR: MP3 encode failed: %v", err)
+			http.Error(w, fmt.Sprintf("Encoding failed: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write(mp3)
+	}
+}
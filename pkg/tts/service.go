@@ -4,7 +4,6 @@
 package tts
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -13,6 +12,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -25,7 +25,10 @@ var (
 // TTSService handles text-to-speech using Piper
 type TTSService struct {
 	config *Config
-	mu     sync.Mutex
+	cache  Cache
+
+	workersMu sync.Mutex
+	workers   map[string][]*piperWorker // pool of persistent piper subprocesses per voice, keyed by voice name
 }
 
 // InitTTSService initializes the global TTS service
@@ -39,26 +42,54 @@ func InitTTSService(cfg *Config) error {
 
 	// Smart path resolution for both dev and production
 	cfg.PiperPath = resolveTTSPath(cfg.PiperPath, "bin/piper")
-	cfg.ModelPath = resolveTTSPath(cfg.ModelPath, "models/tts/en_GB-northern_english_male-medium.onnx")
 
 	// Verify piper executable exists
 	if _, err := os.Stat(cfg.PiperPath); os.IsNotExist(err) {
 		return fmt.Errorf("piper executable not found at: %s", cfg.PiperPath)
 	}
 
-	// Verify model file exists
-	if _, err := os.Stat(cfg.ModelPath); os.IsNotExist(err) {
-		return fmt.Errorf("model file not found at: %s", cfg.ModelPath)
+	if len(cfg.Voices) == 0 {
+		return fmt.Errorf("no voices configured")
+	}
+	if _, ok := cfg.Voices[cfg.DefaultVoice]; !ok {
+		return fmt.Errorf("defaultVoice %q not found in voices", cfg.DefaultVoice)
+	}
+
+	for name, voice := range cfg.Voices {
+		voice.ModelPath = resolveTTSPath(voice.ModelPath, filepath.Join("models/tts", filepath.Base(voice.ModelPath)))
+		if _, err := os.Stat(voice.ModelPath); os.IsNotExist(err) {
+			return fmt.Errorf("model file not found for voice %q: %s", name, voice.ModelPath)
+		}
+		cfg.Voices[name] = voice
+	}
+
+	var cache Cache
+	if cfg.CacheDir != "" {
+		diskCache, err := NewDiskCache(cfg.CacheDir)
+		if err != nil {
+			return err
+		}
+		cache = diskCache
+	} else {
+		cache = NewMemoryCache()
 	}
 
 	globalService = &TTSService{
-		config: cfg,
+		config:  cfg,
+		cache:   cache,
+		workers: make(map[string][]*piperWorker),
 	}
 
-	log.Printf("[TTS] Service initialized with piper: %s, model: %s", cfg.PiperPath, cfg.ModelPath)
+	log.Printf("[TTS] Service initialized with piper: %s, %d voice(s), default: %s", cfg.PiperPath, len(cfg.Voices), cfg.DefaultVoice)
 	return nil
 }
 
+// Cache returns the service's audio cache, so callers (e.g. SynthesizeHandler)
+// can check it before calling Synthesize.
+func (s *TTSService) Cache() Cache {
+	return s.cache
+}
+
 // resolveTTSPath resolves paths for both dev and production environments
 func resolveTTSPath(configPath, fallbackRelPath string) string {
 	// If absolute path exists, use it (dev mode)
@@ -97,111 +128,228 @@ func (s *TTSService) setEspeakEnv(cmd *exec.Cmd) {
 	// Calculate espeak-ng data path
 	// In dev: alfred/bin/piper -> alfred/third_party/piper/build_go/pi/share/espeak-ng-data
 	// In prod: resources/bin/piper -> resources/espeak-ng-data
-	
+
 	piperDir := filepath.Dir(s.config.PiperPath)
-	
+
 	// Try production path first (relative to bin/)
 	espeakDataPath := filepath.Join(piperDir, "..", "espeak-ng-data")
-	
+
 	// If that doesn't exist, try dev path
 	if _, err := os.Stat(espeakDataPath); os.IsNotExist(err) {
 		espeakDataPath = filepath.Join(piperDir, "..", "third_party", "piper", "build_go", "pi", "share", "espeak-ng-data")
 	}
-	
+
 	// Make absolute
 	if absPath, err := filepath.Abs(espeakDataPath); err == nil {
 		espeakDataPath = absPath
 	}
-	
+
 	log.Printf("[TTS] Using ESPEAK_DATA_PATH: %s", espeakDataPath)
 	cmd.Env = append(os.Environ(), "ESPEAK_DATA_PATH="+espeakDataPath)
 }
 
-// SynthesizeToFile synthesizes text to a WAV file
-func (s *TTSService) SynthesizeToFile(ctx context.Context, text string, outputPath string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	// Create command: echo "text" | piper --model model.onnx --output_file output.wav --length-scale X
-	cmd := exec.CommandContext(ctx, s.config.PiperPath,
-		"--model", s.config.ModelPath,
-		"--output_file", outputPath,
-		"--length-scale", fmt.Sprintf("%.2f", s.config.LengthScale),
-	)
+// SynthesizeOptions customizes a single synthesis call. Any zero-valued
+// field falls back to the selected voice's configured default.
+type SynthesizeOptions struct {
+	Voice        string  // Key into Config.Voices; empty uses LanguageCode/Gender to auto-select, then Config.DefaultVoice
+	LanguageCode string  // BCP-47 tag (e.g. "en-GB") used to auto-select a voice when Voice is empty
+	Gender       string  // "male", "female", or "neutral", used to auto-select a voice when Voice is empty
+	SpeakingRate float32 // 1.0 = normal rate; overrides LengthScale (as 1/SpeakingRate) when > 0 and LengthScale is unset
+	Pitch        float32 // reserved: piper has no pitch control, so this is accepted but not yet applied
+	LengthScale  float64 // Overrides the voice's default length scale when > 0
+	SpeakerID    int     // Overrides the voice's default speaker id when > 0
+	NoiseScale   float64 // piper --noise-scale override, 0 = use piper's default
+	NoiseW       float64 // piper --noise-w override, 0 = use piper's default
+	SSML         bool    // When true, text is treated as SSML markup (<speak>...</speak>) instead of plain text
+}
 
-	// Set espeak-ng data path
-	s.setEspeakEnv(cmd)
+// resolveVoice looks up a voice by name, falling back to the configured
+// default voice when name is empty.
+func (s *TTSService) resolveVoice(name string) (VoiceConfig, error) {
+	if name == "" {
+		name = s.config.DefaultVoice
+	}
+	voice, ok := s.config.Voices[name]
+	if !ok {
+		return VoiceConfig{}, fmt.Errorf("unknown voice: %s", name)
+	}
+	return voice, nil
+}
 
-	// Pipe text to stdin
-	cmd.Stdin = bytes.NewBufferString(text)
-	
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
+// resolveVoiceName picks which voice key opts should use: an explicit
+// opts.Voice wins; otherwise the first configured voice matching
+// LanguageCode/Gender, in sorted name order, is used; otherwise
+// Config.DefaultVoice. Voices is a map, so candidates are sorted before
+// picking rather than relying on Go's randomized map iteration order,
+// which would otherwise make the selected voice -- and so the cache key --
+// vary nondeterministically across calls with the same opts.
+func (s *TTSService) resolveVoiceName(opts SynthesizeOptions) string {
+	if opts.Voice != "" {
+		return opts.Voice
+	}
+	if opts.LanguageCode != "" || opts.Gender != "" {
+		names := make([]string, 0, len(s.config.Voices))
+		for name := range s.config.Voices {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			v := s.config.Voices[name]
+			if opts.LanguageCode != "" && !strings.EqualFold(v.Language, opts.LanguageCode) {
+				continue
+			}
+			if opts.Gender != "" && !strings.EqualFold(v.Gender, opts.Gender) {
+				continue
+			}
+			return name
+		}
+	}
+	return s.config.DefaultVoice
+}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("piper command failed: %w, stderr: %s", err, stderr.String())
+// normalizeOptions resolves opts.Voice (via resolveVoiceName) and derives
+// LengthScale from SpeakingRate when the caller didn't set one directly, so
+// downstream code only ever has to deal with a concrete voice name and
+// length scale.
+func (s *TTSService) normalizeOptions(opts SynthesizeOptions) SynthesizeOptions {
+	opts.Voice = s.resolveVoiceName(opts)
+	if opts.SpeakingRate > 0 && opts.LengthScale == 0 {
+		opts.LengthScale = 1.0 / float64(opts.SpeakingRate)
 	}
+	return opts
+}
 
-	return nil
+// getWorker returns the persistent piper worker for the given voice name
+// (falling back to the default voice), lazily starting it on first use or
+// restarting it if a previous instance crashed.
+func (s *TTSService) getWorker(name string) (*piperWorker, VoiceConfig, error) {
+	return s.getWorkerSlot(name, 0)
 }
 
-// Synthesize synthesizes text and returns the audio data as bytes
-func (s *TTSService) Synthesize(ctx context.Context, text string) ([]byte, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// getWorkerSlot returns the persistent piper worker occupying the given
+// slot in the voice's worker pool, lazily starting it (and growing the
+// pool) on first use or restarting it if a previous instance crashed.
+// ChunkedSynthesize requests slots 0 through Parallelism-1 so its concurrent
+// chunks fan out across distinct piper processes instead of queuing behind
+// one worker's mutex.
+func (s *TTSService) getWorkerSlot(name string, slot int) (*piperWorker, VoiceConfig, error) {
+	voice, err := s.resolveVoice(name)
+	if err != nil {
+		return nil, VoiceConfig{}, err
+	}
+	if name == "" {
+		name = s.config.DefaultVoice
+	}
 
-	// Create command: echo "text" | piper --model model.onnx --output-raw --length-scale X
-	cmd := exec.CommandContext(ctx, s.config.PiperPath,
-		"--model", s.config.ModelPath,
-		"--output-raw",
-		"--length-scale", fmt.Sprintf("%.2f", s.config.LengthScale),
-	)
+	s.workersMu.Lock()
+	defer s.workersMu.Unlock()
 
-	// Set espeak-ng data path
-	s.setEspeakEnv(cmd)
+	pool := s.workers[name]
+	for len(pool) <= slot {
+		pool = append(pool, nil)
+	}
 
-	// Pipe text to stdin
-	cmd.Stdin = bytes.NewBufferString(text)
-	
-	var stdout bytes.Buffer
-	var stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	if w := pool[slot]; w != nil && !w.isDead() {
+		return w, voice, nil
+	}
 
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("piper command failed: %w, stderr: %s", err, stderr.String())
+	w, err := s.startWorker(name, voice)
+	if err != nil {
+		return nil, VoiceConfig{}, err
 	}
+	pool[slot] = w
+	s.workers[name] = pool
+	return w, voice, nil
+}
 
-	return stdout.Bytes(), nil
+// SynthesizeToFile synthesizes text (or, with opts.SSML, an SSML document)
+// to a WAV file
+func (s *TTSService) SynthesizeToFile(ctx context.Context, text string, opts SynthesizeOptions, outputPath string) error {
+	opts = s.normalizeOptions(opts)
+
+	if opts.SSML {
+		voice, err := s.resolveVoice(opts.Voice)
+		if err != nil {
+			return err
+		}
+		_, err = s.synthesizeSSML(ctx, text, opts, voice, outputPath)
+		return err
+	}
+
+	worker, voice, err := s.getWorker(opts.Voice)
+	if err != nil {
+		return err
+	}
+	_, err = worker.synthesize(ctx, text, opts, voice, outputPath)
+	return err
 }
 
-// SynthesizeStream synthesizes text and streams audio data
-func (s *TTSService) SynthesizeStream(ctx context.Context, text string, writer io.Writer) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// Synthesize synthesizes text (or, with opts.SSML, an SSML document) and
+// returns the raw PCM audio data
+func (s *TTSService) Synthesize(ctx context.Context, text string, opts SynthesizeOptions) ([]byte, error) {
+	opts = s.normalizeOptions(opts)
+	if opts.SSML {
+		return s.synthesizeSSMLPCM(ctx, text, opts)
+	}
+	return s.synthesizeSlotPCM(ctx, text, opts, 0)
+}
 
-	// Create command: echo "text" | piper --model model.onnx --output-raw --length-scale X
-	cmd := exec.CommandContext(ctx, s.config.PiperPath,
-		"--model", s.config.ModelPath,
-		"--output-raw",
-		"--length-scale", fmt.Sprintf("%.2f", s.config.LengthScale),
-	)
+// synthesizeSSMLPCM renders an SSML document via piper's one-off --ssml
+// mode and returns the raw PCM payload.
+func (s *TTSService) synthesizeSSMLPCM(ctx context.Context, ssml string, opts SynthesizeOptions) ([]byte, error) {
+	tmpFile, err := os.CreateTemp("", "alfred-tts-*.wav")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp output file: %w", err)
+	}
+	outputPath := tmpFile.Name()
+	tmpFile.Close()
+	defer os.Remove(outputPath)
 
-	// Set espeak-ng data path
-	s.setEspeakEnv(cmd)
+	voice, err := s.resolveVoice(opts.Voice)
+	if err != nil {
+		return nil, err
+	}
+	wavBytes, err := s.synthesizeSSML(ctx, ssml, opts, voice, outputPath)
+	if err != nil {
+		return nil, err
+	}
+	return stripWAVHeader(wavBytes), nil
+}
 
-	// Pipe text to stdin
-	cmd.Stdin = bytes.NewBufferString(text)
-	cmd.Stdout = writer
-	
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
+// synthesizeSlotPCM synthesizes text using the worker occupying the given
+// slot in opts.Voice's worker pool and returns the raw PCM payload.
+// ChunkedSynthesize passes distinct slots per concurrent chunk so they fan
+// out across separate piper processes instead of serializing behind one
+// worker's mutex.
+func (s *TTSService) synthesizeSlotPCM(ctx context.Context, text string, opts SynthesizeOptions, slot int) ([]byte, error) {
+	tmpFile, err := os.CreateTemp("", "alfred-tts-*.wav")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp output file: %w", err)
+	}
+	outputPath := tmpFile.Name()
+	tmpFile.Close()
+	defer os.Remove(outputPath)
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("piper command failed: %w, stderr: %s", err, stderr.String())
+	worker, voice, err := s.getWorkerSlot(opts.Voice, slot)
+	if err != nil {
+		return nil, err
 	}
+	wavBytes, err := worker.synthesize(ctx, text, opts, voice, outputPath)
+	if err != nil {
+		return nil, err
+	}
+	return stripWAVHeader(wavBytes), nil
+}
 
-	return nil
+// SynthesizeStream synthesizes text and writes the raw PCM audio data to writer
+func (s *TTSService) SynthesizeStream(ctx context.Context, text string, opts SynthesizeOptions, writer io.Writer) error {
+	pcm, err := s.Synthesize(ctx, text, opts)
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(pcm)
+	return err
 }
 
 // ExtractVerbalResponse extracts text within [[VERBAL]] tags, or returns the first sentence if no tags
@@ -217,12 +365,12 @@ func ExtractVerbalResponse(text string) string {
 	// Split by double newline first (paragraph break)
 	paragraphs := strings.Split(text, "\n\n")
 	firstParagraph := paragraphs[0]
-	
+
 	// If first paragraph is reasonable length, use it
 	if len(firstParagraph) < 1000 {
 		return strings.TrimSpace(firstParagraph)
 	}
-	
+
 	// Otherwise take first 5 sentences
 	sentences := regexp.MustCompile(`[.!?]+\s+`).Split(firstParagraph, 6)
 	if len(sentences) > 0 {
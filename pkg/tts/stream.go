@@ -0,0 +1,194 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// defaultMaxSentenceChars bounds how much text StreamFromTokens buffers
+// before forcing a synthesis flush, so a reply with no punctuation doesn't
+// stall playback indefinitely.
+const defaultMaxSentenceChars = 400
+
+// sentenceTerminators are the token suffixes StreamFromTokens treats as the
+// end of a sentence worth synthesizing immediately.
+var sentenceTerminators = []string{".", "!", "?", "\n\n"}
+
+// StreamFromTokensOptions customizes StreamFromTokens.
+type StreamFromTokensOptions struct {
+	Voice            string // voice to synthesize with; empty uses the default voice
+	Format           string // "mp3" or "opus"; defaults to "mp3"
+	ID3Title         string // when non-empty and Format is "mp3", prepend an ID3v2 title frame
+	MaxSentenceChars int    // hard cap on buffered chars before forcing a flush; 0 uses a sane default
+}
+
+// StreamFromTokens consumes an LLM's incremental token stream, synthesizes
+// each completed sentence as soon as it's available via the persistent
+// piper worker, and writes continuously encoded audio (mp3 or opus) to w so
+// a browser <audio> element can start playback after the first sentence
+// instead of waiting for the entire reply.
+func (s *TTSService) StreamFromTokens(ctx context.Context, tokenCh <-chan string, w io.Writer, opts StreamFromTokensOptions) error {
+	maxChars := opts.MaxSentenceChars
+	if maxChars <= 0 {
+		maxChars = defaultMaxSentenceChars
+	}
+
+	if opts.ID3Title != "" && opts.Format != "opus" {
+		if _, err := w.Write(buildID3v2TitleFrame(opts.ID3Title)); err != nil {
+			return fmt.Errorf("failed to write ID3 header: %w", err)
+		}
+	}
+
+	encoderIn, encoderDone, err := startStreamEncoder(ctx, opts.Format, s.config.SampleRate, w)
+	if err != nil {
+		return err
+	}
+
+	var buf strings.Builder
+	flush := func() error {
+		text := strings.TrimSpace(buf.String())
+		buf.Reset()
+		if text == "" {
+			return nil
+		}
+		pcm, err := s.Synthesize(ctx, text, SynthesizeOptions{Voice: opts.Voice})
+		if err != nil {
+			return fmt.Errorf("failed to synthesize sentence %q: %w", text, err)
+		}
+		_, err = encoderIn.Write(pcm)
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			encoderIn.Close()
+			<-encoderDone
+			return ctx.Err()
+
+		case token, ok := <-tokenCh:
+			if !ok {
+				err := flush()
+				encoderIn.Close()
+				if encErr := <-encoderDone; err == nil {
+					err = encErr
+				}
+				return err
+			}
+			buf.WriteString(token)
+			if endsWithSentenceTerminator(buf.String()) || buf.Len() >= maxChars {
+				if err := flush(); err != nil {
+					encoderIn.Close()
+					<-encoderDone
+					return err
+				}
+			}
+		}
+	}
+}
+
+func endsWithSentenceTerminator(text string) bool {
+	trimmed := strings.TrimRight(text, " \t")
+	for _, term := range sentenceTerminators {
+		if strings.HasSuffix(trimmed, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// startStreamEncoder launches ffmpeg reading raw s16le PCM on stdin and
+// writing continuously encoded audio to w as it becomes available.
+func startStreamEncoder(ctx context.Context, format string, sampleRate int, w io.Writer) (io.WriteCloser, <-chan error, error) {
+	codecArgs := []string{"-f", "mp3", "-b:a", "96k"}
+	if format == "opus" {
+		codecArgs = []string{"-f", "ogg", "-c:a", "libopus", "-b:a", "64k"}
+	}
+
+	args := append([]string{
+		"-f", "s16le",
+		"-ar", fmt.Sprintf("%d", sampleRate),
+		"-ac", "1",
+		"-i", "pipe:0",
+	}, codecArgs...)
+	args = append(args, "pipe:1")
+
+	cmd := exec.CommandContext(ctx, "ffmpeg", args...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open ffmpeg stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open ffmpeg stdout: %w", err)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, nil, fmt.Errorf("failed to start ffmpeg: %w", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, copyErr := io.Copy(w, stdout)
+		waitErr := cmd.Wait()
+		switch {
+		case copyErr != nil:
+			done <- copyErr
+		case waitErr != nil:
+			done <- fmt.Errorf("ffmpeg failed: %w, stderr: %s", waitErr, stderr.String())
+		default:
+			done <- nil
+		}
+	}()
+
+	return stdin, done, nil
+}
+
+// buildID3v2TitleFrame builds a minimal ID3v2.3 tag containing a single
+// title (TIT2) frame, so clients that want a "now speaking" display have
+// something to read before the rest of the audio arrives.
+func buildID3v2TitleFrame(title string) []byte {
+	const maxTitleLen = 64
+	if len(title) > maxTitleLen {
+		title = title[:maxTitleLen]
+	}
+
+	content := append([]byte{0}, []byte(title)...) // encoding byte (0 = ISO-8859-1) + text
+
+	frame := new(bytes.Buffer)
+	frame.WriteString("TIT2")
+	frameSize := make([]byte, 4)
+	binary.BigEndian.PutUint32(frameSize, uint32(len(content)))
+	frame.Write(frameSize)
+	frame.Write([]byte{0, 0}) // flags
+	frame.Write(content)
+
+	header := new(bytes.Buffer)
+	header.WriteString("ID3")
+	header.Write([]byte{3, 0, 0}) // version 2.3.0, flags
+	header.Write(synchsafe(uint32(frame.Len())))
+
+	return append(header.Bytes(), frame.Bytes()...)
+}
+
+// synchsafe encodes size as an ID3v2 synchsafe 32-bit integer (7 bits per byte).
+func synchsafe(size uint32) []byte {
+	return []byte{
+		byte((size >> 21) & 0x7F),
+		byte((size >> 14) & 0x7F),
+		byte((size >> 7) & 0x7F),
+		byte(size & 0x7F),
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package tts
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// streamChunkMaxChars bounds how much text one /tts/stream frame carries,
+// so playback can start after the first sentence or two instead of waiting
+// for the whole reply.
+const streamChunkMaxChars = 200
+
+var streamUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(r *http.Request) bool {
+		// Allow all origins for WebSocket connections
+		// In production, you should validate the origin
+		return true
+	},
+}
+
+// streamControlMessage is a JSON control message sent by the client over
+// /tts/stream.
+type streamControlMessage struct {
+	Type  string `json:"type"` // "start", "stop", or "cancel"
+	Text  string `json:"text,omitempty"`
+	Voice string `json:"voice,omitempty"`
+	SSML  bool   `json:"ssml,omitempty"`
+}
+
+// StreamWebSocketHandler handles /tts/stream. Clients send JSON control
+// messages ({"type":"start",...}, {"type":"stop"}, {"type":"cancel"}) and
+// receive binary PCM frames, one per synthesized chunk, so playback can
+// begin before the full reply has been synthesized. A "stop"/"cancel" (or a
+// new "start") aborts any synthesis already in flight.
+func StreamWebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	service := GetTTSService()
+	if service == nil {
+		log.Printf("[TTS] ERROR: Service not initialized")
+		http.Error(w, "TTS service not initialized", http.StatusServiceUnavailable)
+		return
+	}
+
+	conn, err := streamUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("[TTS] ERROR: stream WebSocket upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	var cancel context.CancelFunc
+	var done chan struct{}
+	defer func() {
+		if cancel != nil {
+			cancel()
+		}
+		if done != nil {
+			<-done
+		}
+	}()
+
+	for {
+		var msg streamControlMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		switch msg.Type {
+		case "start":
+			if cancel != nil {
+				cancel() // a new start supersedes any synthesis still in flight
+				<-done   // wait for it to stop writing to conn before the next goroutine starts
+			}
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			done = make(chan struct{})
+			go func(ctx context.Context, done chan struct{}, msg streamControlMessage) {
+				defer close(done)
+				streamSynthesisToWebSocket(ctx, service, conn, msg)
+			}(ctx, done, msg)
+		case "stop", "cancel":
+			if cancel != nil {
+				cancel()
+				<-done
+				cancel = nil
+				done = nil
+			}
+		default:
+			log.Printf("[TTS] unknown stream control message type: %q", msg.Type)
+		}
+	}
+}
+
+// streamSynthesisToWebSocket splits msg.Text into small chunks, synthesizes
+// each in turn, and writes it as a binary PCM frame as soon as it's ready.
+// It stops early if ctx is canceled by a "stop"/"cancel" control message or
+// a new "start".
+func streamSynthesisToWebSocket(ctx context.Context, service *TTSService, conn *websocket.Conn, msg streamControlMessage) {
+	opts := SynthesizeOptions{Voice: msg.Voice, SSML: msg.SSML}
+
+	var chunks []string
+	if msg.SSML {
+		chunks = splitSSMLChunks(msg.Text, streamChunkMaxChars)
+	} else {
+		chunks = splitTextChunks(msg.Text, streamChunkMaxChars)
+	}
+
+	for _, chunk := range chunks {
+		if ctx.Err() != nil {
+			return
+		}
+
+		pcm, err := service.Synthesize(ctx, chunk, opts)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("[TTS] stream synthesis error: %v", err)
+				conn.WriteJSON(map[string]string{"type": "error", "message": err.Error()})
+			}
+			return
+		}
+
+		if err := conn.WriteMessage(websocket.BinaryMessage, pcm); err != nil {
+			log.Printf("[TTS] error writing stream frame: %v", err)
+			return
+		}
+	}
+
+	conn.WriteJSON(map[string]string{"type": "done"})
+}
@@ -0,0 +1,90 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package tts
+
+import (
+	"log"
+	"net/http"
+)
+
+// StreamTokensHandler handles POST /tts/stream-tokens. The request body is
+// an LLM's token stream delivered as a chunked HTTP request -- there's no
+// framing, so whatever one read of the body returns (at most 256 bytes) is
+// treated as one token. The response is continuously encoded audio (mp3 by default, or
+// opus via ?format=opus) written as soon as each completed sentence is
+// synthesized, via TTSService.StreamFromTokens.
+func StreamTokensHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	service := GetTTSService()
+	if service == nil {
+		log.Printf("[TTS] ERROR: Service not initialized")
+		http.Error(w, "TTS service not initialized", http.StatusServiceUnavailable)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	format := "mp3"
+	if negotiateFormat(r) == "opus" {
+		format = "opus"
+	}
+	contentType := "audio/mpeg"
+	if format == "opus" {
+		contentType = "audio/ogg"
+	}
+
+	opts := StreamFromTokensOptions{
+		Voice:    r.URL.Query().Get("voice"),
+		Format:   format,
+		ID3Title: r.URL.Query().Get("title"),
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+
+	tokenCh := make(chan string)
+	go func() {
+		defer close(tokenCh)
+		buf := make([]byte, 256)
+		for {
+			n, err := r.Body.Read(buf)
+			if n > 0 {
+				select {
+				case tokenCh <- string(buf[:n]):
+				case <-r.Context().Done():
+					return
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	if err := service.StreamFromTokens(r.Context(), tokenCh, flushWriter{w, flusher}, opts); err != nil {
+		log.Printf("[TTS] ERROR: token stream synthesis failed: %v", err)
+	}
+}
+
+// flushWriter flushes after every Write so StreamFromTokens's per-sentence
+// output reaches the client as soon as it's produced, instead of sitting in
+// a buffer until the handler returns.
+type flushWriter struct {
+	w http.ResponseWriter
+	f http.Flusher
+}
+
+func (fw flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	fw.f.Flush()
+	return n, err
+}
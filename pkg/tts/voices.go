@@ -0,0 +1,55 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package tts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// VoiceInfo describes a configured voice for API consumers, e.g. to
+// populate a voice picker in the UI.
+type VoiceInfo struct {
+	Name       string `json:"name"`
+	Language   string `json:"language,omitempty"`
+	Gender     string `json:"gender,omitempty"`
+	SampleRate int    `json:"sampleRate"`
+	Default    bool   `json:"default"`
+}
+
+// ListVoices returns the configured voices, for a front-end settings panel
+// to offer a choice of voice/language/gender.
+func (s *TTSService) ListVoices(ctx context.Context) ([]VoiceInfo, error) {
+	voices := make([]VoiceInfo, 0, len(s.config.Voices))
+	for name, v := range s.config.Voices {
+		voices = append(voices, VoiceInfo{
+			Name:       name,
+			Language:   v.Language,
+			Gender:     v.Gender,
+			SampleRate: s.config.SampleRate,
+			Default:    name == s.config.DefaultVoice,
+		})
+	}
+	return voices, nil
+}
+
+// ListVoicesHandler handles GET /tts/voices (and /api/tts/voices) and
+// returns the configured voices as JSON.
+func ListVoicesHandler(w http.ResponseWriter, r *http.Request) {
+	service := GetTTSService()
+	if service == nil {
+		http.Error(w, "TTS service not initialized", http.StatusServiceUnavailable)
+		return
+	}
+
+	voices, err := service.ListVoices(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(voices)
+}
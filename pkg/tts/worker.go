@@ -0,0 +1,241 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// piperWorker is a long-lived piper subprocess for a single voice, launched
+// once in --json-input mode so repeated utterances skip per-call process
+// startup. Requests are serialized through mu since piper's json-input loop
+// handles one line at a time.
+type piperWorker struct {
+	mu    sync.Mutex
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	voice string
+
+	deadMu sync.Mutex
+	dead   bool
+}
+
+// piperLineRequest is one line of piper's --json-input protocol.
+type piperLineRequest struct {
+	Text        string   `json:"text"`
+	OutputFile  string   `json:"output_file"`
+	SpeakerID   *int     `json:"speaker_id,omitempty"`
+	LengthScale *float64 `json:"length_scale,omitempty"`
+	NoiseScale  *float64 `json:"noise_scale,omitempty"`
+	NoiseW      *float64 `json:"noise_w,omitempty"`
+}
+
+// startWorker launches a persistent piper process for the given voice and
+// wires up a supervisor goroutine that marks the worker dead when piper
+// exits, so the next request restarts it.
+func (s *TTSService) startWorker(name string, voice VoiceConfig) (*piperWorker, error) {
+	args := []string{"--model", voice.ModelPath, "--json-input"}
+	if voice.ConfigPath != "" {
+		args = append(args, "--config", voice.ConfigPath)
+	}
+
+	cmd := exec.Command(s.config.PiperPath, args...)
+	s.setEspeakEnv(cmd)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open piper stdin: %w", err)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start piper worker: %w", err)
+	}
+
+	w := &piperWorker{cmd: cmd, stdin: stdin, voice: name}
+
+	go func() {
+		err := cmd.Wait()
+		w.deadMu.Lock()
+		w.dead = true
+		w.deadMu.Unlock()
+		if err != nil {
+			log.Printf("[TTS] piper worker for voice %q exited: %v, stderr: %s", name, err, stderr.String())
+		} else {
+			log.Printf("[TTS] piper worker for voice %q exited", name)
+		}
+	}()
+
+	log.Printf("[TTS] started persistent piper worker for voice %q (pid %d)", name, cmd.Process.Pid)
+	return w, nil
+}
+
+func (w *piperWorker) isDead() bool {
+	w.deadMu.Lock()
+	defer w.deadMu.Unlock()
+	return w.dead
+}
+
+// synthesize sends one utterance to the worker's stdin and waits for piper
+// to finish writing outputPath, returning the resulting WAV bytes.
+func (w *piperWorker) synthesize(ctx context.Context, text string, opts SynthesizeOptions, voice VoiceConfig, outputPath string) ([]byte, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.isDead() {
+		return nil, fmt.Errorf("piper worker for voice %q has crashed", w.voice)
+	}
+
+	req := piperLineRequest{Text: text, OutputFile: outputPath}
+
+	lengthScale := voice.LengthScale
+	if opts.LengthScale > 0 {
+		lengthScale = opts.LengthScale
+	}
+	if lengthScale > 0 {
+		req.LengthScale = &lengthScale
+	}
+
+	speakerID := voice.SpeakerID
+	if opts.SpeakerID > 0 {
+		speakerID = opts.SpeakerID
+	}
+	if speakerID > 0 {
+		req.SpeakerID = &speakerID
+	}
+
+	if opts.NoiseScale > 0 {
+		req.NoiseScale = &opts.NoiseScale
+	}
+	if opts.NoiseW > 0 {
+		req.NoiseW = &opts.NoiseW
+	}
+
+	line, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal piper request: %w", err)
+	}
+	line = append(line, '\n')
+
+	if _, err := w.stdin.Write(line); err != nil {
+		return nil, fmt.Errorf("failed to write to piper worker: %w", err)
+	}
+
+	if err := waitForStableFile(ctx, outputPath, w.isDead); err != nil {
+		return nil, fmt.Errorf("piper worker for voice %q: %w", w.voice, err)
+	}
+
+	return os.ReadFile(outputPath)
+}
+
+// synthesizeSSML renders an SSML document (e.g. "<speak>Hello<break
+// time=\"500ms\"/>world.</speak>") to outputPath. Piper's --ssml mode reads
+// markup from stdin and writes a single file per invocation rather than
+// speaking its --json-input protocol, so SSML requests bypass the
+// persistent worker and run piper as a one-off process instead.
+func (s *TTSService) synthesizeSSML(ctx context.Context, ssml string, opts SynthesizeOptions, voice VoiceConfig, outputPath string) ([]byte, error) {
+	args := []string{"--model", voice.ModelPath, "--ssml", "--output_file", outputPath}
+	if voice.ConfigPath != "" {
+		args = append(args, "--config", voice.ConfigPath)
+	}
+
+	lengthScale := voice.LengthScale
+	if opts.LengthScale > 0 {
+		lengthScale = opts.LengthScale
+	}
+	if lengthScale > 0 {
+		args = append(args, "--length-scale", fmt.Sprintf("%g", lengthScale))
+	}
+
+	speakerID := voice.SpeakerID
+	if opts.SpeakerID > 0 {
+		speakerID = opts.SpeakerID
+	}
+	if speakerID > 0 {
+		args = append(args, "--speaker", fmt.Sprintf("%d", speakerID))
+	}
+
+	if opts.NoiseScale > 0 {
+		args = append(args, "--noise-scale", fmt.Sprintf("%g", opts.NoiseScale))
+	}
+	if opts.NoiseW > 0 {
+		args = append(args, "--noise-w", fmt.Sprintf("%g", opts.NoiseW))
+	}
+
+	cmd := exec.CommandContext(ctx, s.config.PiperPath, args...)
+	s.setEspeakEnv(cmd)
+	cmd.Stdin = strings.NewReader(ssml)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("piper ssml synthesis failed: %w, stderr: %s", err, stderr.String())
+	}
+
+	return os.ReadFile(outputPath)
+}
+
+// waitForStableFile polls outputPath until it exists and its size has
+// stopped growing across consecutive checks, which is how we detect that
+// piper has finished writing a request's audio since the json-input
+// protocol gives us no explicit completion signal. It bails out as soon as
+// isDead reports the worker has crashed, rather than polling until the
+// caller's context deadline, so an in-flight request fails fast instead of
+// waiting out a multi-minute chunked-synthesis timeout for a file that will
+// never finish writing.
+func waitForStableFile(ctx context.Context, path string, isDead func() bool) error {
+	const pollInterval = 20 * time.Millisecond
+
+	var lastSize int64 = -1
+	stableChecks := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+
+		if isDead() {
+			return fmt.Errorf("worker crashed while waiting for output")
+		}
+
+		info, err := os.Stat(path)
+		if err != nil || info.Size() == 0 {
+			stableChecks = 0
+			continue
+		}
+		if info.Size() == lastSize {
+			stableChecks++
+			if stableChecks >= 2 {
+				return nil
+			}
+		} else {
+			stableChecks = 0
+		}
+		lastSize = info.Size()
+	}
+}
+
+// stripWAVHeader returns the PCM sample payload of a RIFF/WAV byte slice,
+// or the input unchanged if it doesn't look like a WAV file.
+func stripWAVHeader(data []byte) []byte {
+	idx := bytes.Index(data, []byte("data"))
+	if idx < 0 || idx+8 > len(data) {
+		return data
+	}
+	return data[idx+8:]
+}
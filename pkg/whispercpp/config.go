@@ -5,26 +5,36 @@ package whispercpp
 
 // Config holds the configuration for the Whisper service
 type Config struct {
-    ModelPath    string  `json:"modelPath"`
-    Threads      int     `json:"threads"`
-    MaxAudioLen  int     `json:"maxAudioLen"` // in seconds
-    SampleRate   int     `json:"sampleRate"`
-    Language     string  `json:"language"`
-    EnableVAD    bool    `json:"enableVAD"`
-    VADThreshold float32 `json:"vadThreshold"`
-    WakeWord     string  `json:"wakeWord"`
+    ModelPath            string  `json:"modelPath"`
+    Threads              int     `json:"threads"`
+    MaxAudioLen          int     `json:"maxAudioLen"` // in seconds
+    SampleRate           int     `json:"sampleRate"`
+    Language             string  `json:"language"`
+    EnableVAD            bool    `json:"enableVAD"`
+    VADThreshold         float32 `json:"vadThreshold"`
+    WakeWord             string  `json:"wakeWord"`
+    RollingWindowSec     float64 `json:"rollingWindowSec"`     // size of the rolling buffer used for partial transcription
+    PartialIntervalMS    int     `json:"partialIntervalMs"`    // how often to emit a partial hypothesis while streaming
+    SilenceFramesToFinal int     `json:"silenceFramesToFinal"` // consecutive silent VAD frames before the buffer is finalized
+    WakeWordMode         string  `json:"wakeWordMode"`         // "substring" (default) or "keyword-spotting"
+    WakeWordCooldownMS   int     `json:"wakeWordCooldownMs"`   // how long the service stays "awake" after a wake event
 }
 
 // DefaultConfig returns a new configuration with default values
 func DefaultConfig() *Config {
     return &Config{
-        ModelPath:    "/Users/zakicole/alfred/models/ggml-base.en.bin",
-        Threads:      4,
-        MaxAudioLen:  30,
-        SampleRate:   16000,
-        Language:     "en",
-        EnableVAD:    true,
-        VADThreshold: 0.5,
-        WakeWord:     "alfred",
+        ModelPath:            "/Users/zakicole/alfred/models/ggml-base.en.bin",
+        Threads:              4,
+        MaxAudioLen:          30,
+        SampleRate:           16000,
+        Language:             "en",
+        EnableVAD:            true,
+        VADThreshold:         0.5,
+        WakeWord:             "alfred",
+        RollingWindowSec:     8,
+        PartialIntervalMS:    500,
+        SilenceFramesToFinal: 6,
+        WakeWordMode:         "substring",
+        WakeWordCooldownMS:   8000,
     }
 }
@@ -118,5 +118,7 @@ func TranscribeHandler(w http.ResponseWriter, r *http.Request) {
 // RegisterRoutes registers the Whisper service HTTP handlers
 func RegisterRoutes(mux *http.ServeMux) {
     mux.HandleFunc("/api/whisper/ws", WebSocketHandler)
+    mux.HandleFunc("/api/whisper/stream", WebSocketStreamHandler)
     mux.HandleFunc("/api/whisper/transcribe", TranscribeHandler)
+    mux.HandleFunc("/v1/audio/transcriptions", OpenAITranscriptionHandler)
 }
@@ -46,34 +46,70 @@ func NewModel(config *Config) (*Model, error) {
 	}, nil
 }
 
-// Transcribe processes audio data and returns the transcribed text
-func (m *Model) Transcribe(samples []float32) (string, error) {
+// TranscribeOptions controls how a batch of samples is processed.
+type TranscribeOptions struct {
+	// SingleSegment restricts whisper to a single emitted segment, which keeps
+	// latency low when transcribing short rolling windows for partial results.
+	SingleSegment bool
+	// Language overrides Config.Language for this call when non-empty (e.g. a
+	// per-request language hint from an OpenAI-compatible client).
+	Language string
+}
+
+// TranscribeSegments processes audio data and returns the transcribed
+// segments with timing information, honoring the given options.
+func (m *Model) TranscribeSegments(samples []float32, opts TranscribeOptions) ([]Segment, error) {
 	if m.model == nil {
-		return "", fmt.Errorf("model not initialized")
+		return nil, fmt.Errorf("model not initialized")
 	}
 
 	ctx, err := m.model.NewContext()
 	if err != nil {
-		return "", fmt.Errorf("failed to create whisper context: %w", err)
+		return nil, fmt.Errorf("failed to create whisper context: %w", err)
 	}
 	// Configure context
-	if m.config.Language != "" {
-		_ = ctx.SetLanguage(m.config.Language)
+	language := m.config.Language
+	if opts.Language != "" {
+		language = opts.Language
+	}
+	if language != "" {
+		_ = ctx.SetLanguage(language)
 	}
 	if m.config.Threads > 0 {
 		ctx.SetThreads(uint(m.config.Threads))
 	}
+	if opts.SingleSegment {
+		ctx.SetSingleSegment(true)
+	}
 
 	if err := ctx.Process(samples, nil, nil, nil); err != nil {
-		return "", fmt.Errorf("failed to process audio: %w", err)
+		return nil, fmt.Errorf("failed to process audio: %w", err)
 	}
 
-	var out string
+	var segments []Segment
 	for {
 		seg, err := ctx.NextSegment()
 		if err != nil {
 			break
 		}
+		segments = append(segments, Segment{
+			Text:  seg.Text,
+			Start: seg.Start.Seconds(),
+			End:   seg.End.Seconds(),
+		})
+	}
+	return segments, nil
+}
+
+// Transcribe processes audio data and returns the transcribed text
+func (m *Model) Transcribe(samples []float32) (string, error) {
+	segments, err := m.TranscribeSegments(samples, TranscribeOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	var out string
+	for _, seg := range segments {
 		out += seg.Text + " "
 	}
 	return out, nil
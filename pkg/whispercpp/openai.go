@@ -0,0 +1,206 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package whispercpp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os/exec"
+	"strings"
+)
+
+// openAISegment mirrors the segment shape returned by OpenAI's
+// verbose_json transcription response.
+type openAISegment struct {
+	ID    int     `json:"id"`
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// openAITranscriptionResponse mirrors OpenAI's /v1/audio/transcriptions
+// response body.
+type openAITranscriptionResponse struct {
+	Text     string          `json:"text"`
+	Language string          `json:"language,omitempty"`
+	Duration float64         `json:"duration,omitempty"`
+	Segments []openAISegment `json:"segments,omitempty"`
+}
+
+// decodeAudioToPCM shells out to ffmpeg to decode an arbitrary audio upload
+// (wav/mp3/flac/ogg/webm/...) to 16kHz mono float32 PCM samples.
+func decodeAudioToPCM(data []byte, sampleRate int) ([]float32, error) {
+	cmd := exec.Command("ffmpeg",
+		"-i", "pipe:0",
+		"-f", "f32le",
+		"-ar", fmt.Sprintf("%d", sampleRate),
+		"-ac", "1",
+		"pipe:1",
+	)
+	cmd.Stdin = bytes.NewReader(data)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg decode failed: %w, stderr: %s", err, stderr.String())
+	}
+
+	samples := make([]float32, stdout.Len()/4)
+	if err := binary.Read(&stdout, binary.LittleEndian, samples); err != nil && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("failed to read decoded samples: %w", err)
+	}
+	return samples, nil
+}
+
+// formatTimestamp renders seconds as an SRT/VTT timestamp.
+func formatTimestamp(seconds float64, commaDecimal bool) string {
+	ms := int(seconds*1000 + 0.5)
+	h := ms / 3600000
+	ms -= h * 3600000
+	m := ms / 60000
+	ms -= m * 60000
+	s := ms / 1000
+	ms -= s * 1000
+
+	sep := "."
+	if commaDecimal {
+		sep = ","
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", h, m, s, sep, ms)
+}
+
+func segmentsToSRT(segments []openAISegment) string {
+	var b strings.Builder
+	for i, seg := range segments {
+		fmt.Fprintf(&b, "%d\n", i+1)
+		fmt.Fprintf(&b, "%s --> %s\n", formatTimestamp(seg.Start, true), formatTimestamp(seg.End, true))
+		fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(seg.Text))
+	}
+	return b.String()
+}
+
+func segmentsToVTT(segments []openAISegment) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n\n")
+	for _, seg := range segments {
+		fmt.Fprintf(&b, "%s --> %s\n", formatTimestamp(seg.Start, false), formatTimestamp(seg.End, false))
+		fmt.Fprintf(&b, "%s\n\n", strings.TrimSpace(seg.Text))
+	}
+	return b.String()
+}
+
+// OpenAITranscriptionHandler implements an OpenAI-compatible
+// /v1/audio/transcriptions endpoint on top of the Whisper model, so any
+// OpenAI SDK client can use Alfred as its transcription backend.
+func OpenAITranscriptionHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	service := GetWhisperService()
+	if service == nil {
+		http.Error(w, "Whisper service not initialized", http.StatusServiceUnavailable)
+		return
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid multipart form: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "file field is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read uploaded file: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	responseFormat := r.FormValue("response_format")
+	if responseFormat == "" {
+		responseFormat = "json"
+	}
+
+	// prompt/temperature are part of the OpenAI contract but have no
+	// equivalent in this whisper.cpp binding; they are accepted and ignored,
+	// with a server-side log line, and the response does not mention them.
+	if r.FormValue("prompt") != "" {
+		log.Printf("[Whisper] 'prompt' is not supported by this backend and will be ignored")
+	}
+	if r.FormValue("temperature") != "" {
+		log.Printf("[Whisper] 'temperature' is not supported by this backend and will be ignored")
+	}
+
+	samples, err := decodeAudioToPCM(data, service.config.SampleRate)
+	if err != nil {
+		log.Printf("[Whisper] ERROR: failed to decode upload: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to decode audio: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// service.mu is held for the whole call, like every other transcription
+	// path in this package, since concurrent NewContext()/Process() calls
+	// against one whisper.Model aren't safe.
+	language := r.FormValue("language")
+	service.mu.Lock()
+	segs, err := service.model.TranscribeSegments(samples, TranscribeOptions{Language: language})
+	service.mu.Unlock()
+	if err != nil {
+		log.Printf("[Whisper] ERROR: transcription failed: %v", err)
+		http.Error(w, fmt.Sprintf("Transcription failed: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	var text strings.Builder
+	oaiSegments := make([]openAISegment, 0, len(segs))
+	for i, seg := range segs {
+		text.WriteString(seg.Text)
+		text.WriteString(" ")
+		oaiSegments = append(oaiSegments, openAISegment{
+			ID:    i,
+			Start: seg.Start,
+			End:   seg.End,
+			Text:  seg.Text,
+		})
+	}
+	fullText := strings.TrimSpace(text.String())
+
+	switch responseFormat {
+	case "text":
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(fullText))
+	case "srt":
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(segmentsToSRT(oaiSegments)))
+	case "vtt":
+		w.Header().Set("Content-Type", "text/vtt")
+		w.Write([]byte(segmentsToVTT(oaiSegments)))
+	case "verbose_json":
+		if language == "" {
+			language = service.config.Language
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(openAITranscriptionResponse{
+			Text:     fullText,
+			Language: language,
+			Segments: oaiSegments,
+		})
+	default: // "json"
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(openAITranscriptionResponse{Text: fullText})
+	}
+}
@@ -5,9 +5,11 @@ package whispercpp
 
 import (
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,27 +19,90 @@ import (
 	"github.com/gorilla/websocket"
 )
 
+// Binary WebSocket frame layout: byte 0 is the message type below. Audio
+// frames are followed by a little-endian header (sample rate uint32,
+// channel count uint8, sample format uint8) and then raw samples; control
+// frames are followed by a single opcode byte.
+const (
+	wsFrameAudio          byte = 0x01
+	wsFrameControl        byte = 0x02
+	wsFrameEndOfUtterance byte = 0x03
+
+	wsControlFlush byte = 0x01
+
+	wsSampleFormatInt16   byte = 0
+	wsSampleFormatFloat32 byte = 1
+)
+
+// decodeBinaryAudioFrame parses a 0x01 audio frame (minus its leading type
+// byte) into a new slice of mono float32 samples, converting int16 PCM
+// (sample / 32768) when the frame is tagged wsSampleFormatInt16.
+func decodeBinaryAudioFrame(frame []byte) ([]float32, error) {
+	const headerLen = 6 // sampleRate(4) + channels(1) + format(1)
+	if len(frame) < headerLen {
+		return nil, fmt.Errorf("audio frame too short: %d bytes", len(frame))
+	}
+
+	// sampleRate is carried for future per-frame resampling; the service
+	// currently assumes everything arrives at s.config.SampleRate.
+	channels := frame[4]
+	format := frame[5]
+	payload := frame[headerLen:]
+
+	if channels != 1 {
+		return nil, fmt.Errorf("unsupported channel count: %d", channels)
+	}
+
+	switch format {
+	case wsSampleFormatFloat32:
+		if len(payload)%4 != 0 {
+			return nil, fmt.Errorf("float32 payload length %d not a multiple of 4", len(payload))
+		}
+		samples := make([]float32, len(payload)/4)
+		for i := range samples {
+			samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(payload[i*4:]))
+		}
+		return samples, nil
+	case wsSampleFormatInt16:
+		if len(payload)%2 != 0 {
+			return nil, fmt.Errorf("int16 payload length %d not a multiple of 2", len(payload))
+		}
+		samples := make([]float32, len(payload)/2)
+		for i := range samples {
+			samples[i] = float32(int16(binary.LittleEndian.Uint16(payload[i*2:]))) / 32768.0
+		}
+		return samples, nil
+	default:
+		return nil, fmt.Errorf("unsupported sample format: %d", format)
+	}
+}
+
 // TranscriptionResult represents a single transcription result
 type TranscriptionResult struct {
 	Text       string  `json:"text"`
 	IsFinal    bool    `json:"isFinal"`
 	IsWakeWord bool    `json:"isWakeWord"`
 	Confidence float64 `json:"confidence,omitempty"`
+	Detector   string  `json:"detector,omitempty"`
 }
 
 // WhisperService handles audio transcription using Whisper.cpp
 type WhisperService struct {
-	config      *Config
-	model       *Model
-	mu          sync.Mutex
+	config       *Config
+	model        *Model
+	mu           sync.Mutex
+	wakeDetector WakeWordDetector
 
-	audioQueue  chan []float32
-	results     chan TranscriptionResult
-	cancelFunc  context.CancelFunc
+	audioQueue chan []float32
+	results    chan TranscriptionResult
+	cancelFunc context.CancelFunc
 
 	running     bool
 	connections map[*websocket.Conn]bool
 	connMutex   sync.RWMutex
+
+	wakeMu     sync.Mutex
+	awakeUntil time.Time
 }
 
 // NewWhisperService creates a new Whisper service instance
@@ -70,14 +135,52 @@ func NewWhisperService(cfg *Config) (*WhisperService, error) {
 	}
 
 	return &WhisperService{
-		config:      cfg,
-		model:       model,
-		audioQueue:  make(chan []float32, 1000),
-		results:     make(chan TranscriptionResult, 100),
-		connections: make(map[*websocket.Conn]bool),
+		config:       cfg,
+		model:        model,
+		wakeDetector: newWakeWordDetector(cfg),
+		audioQueue:   make(chan []float32, 1000),
+		results:      make(chan TranscriptionResult, 100),
+		connections:  make(map[*websocket.Conn]bool),
 	}, nil
 }
 
+// newWakeWordDetector builds the configured WakeWordDetector implementation.
+func newWakeWordDetector(cfg *Config) WakeWordDetector {
+	if cfg.WakeWordMode == "keyword-spotting" {
+		return NewKeywordSpottingDetector(cfg.ModelPath, 0.5)
+	}
+	return NewSubstringDetector(
+		"initiate "+cfg.WakeWord+" protocol",
+		"hi "+cfg.WakeWord,
+		"hey "+cfg.WakeWord,
+	)
+}
+
+// isAwake reports whether the service is within its post-wake cooldown
+// window, during which all audio is routed to full transcription.
+func (s *WhisperService) isAwake() bool {
+	s.wakeMu.Lock()
+	defer s.wakeMu.Unlock()
+	return time.Now().Before(s.awakeUntil)
+}
+
+// wake starts (or extends) the post-wake cooldown window and broadcasts a
+// "listening" state to the given connection.
+func (s *WhisperService) wake(conn *websocket.Conn) {
+	s.wakeMu.Lock()
+	expires := time.Now().Add(time.Duration(s.config.WakeWordCooldownMS) * time.Millisecond)
+	s.awakeUntil = expires
+	s.wakeMu.Unlock()
+
+	if err := conn.WriteJSON(map[string]interface{}{
+		"type":    "state",
+		"state":   "awake",
+		"expires": expires.UnixMilli(),
+	}); err != nil {
+		log.Printf("[Whisper] error broadcasting awake state: %v", err)
+	}
+}
+
 // resolveWhisperPath resolves paths for both dev and production environments
 func resolveWhisperPath(configPath, fallbackRelPath string) string {
 	// If absolute path exists, use it (dev mode)
@@ -212,24 +315,157 @@ func (s *WhisperService) transcribeAudio(audioData []float32) {
 		return
 	}
 
-	// Check for wake words
-	lowerText := strings.ToLower(strings.TrimSpace(text))
-	isWakeWord := strings.Contains(lowerText, "initiate alfred protocol") ||
-		strings.Contains(lowerText, "hi alfred") ||
-		strings.Contains(lowerText, "hey alfred")
-
+	isWakeWord, confidence := s.wakeDetector.Detect(text, audioData)
 	if isWakeWord {
-		log.Printf("[Whisper] Wake word detected: %s", text)
+		log.Printf("[Whisper] Wake word detected by %s (confidence %.2f): %s", s.wakeDetector.Name(), confidence, text)
 	}
 
 	result := TranscriptionResult{
 		Text:       text,
 		IsFinal:    true,
 		IsWakeWord: isWakeWord,
+		Confidence: confidence,
+		Detector:   s.wakeDetector.Name(),
 	}
 	s.results <- result
 }
 
+// rollingState tracks the per-connection sliding audio buffer used to produce
+// low-latency partial transcriptions between finals.
+type rollingState struct {
+	buffer       []float32
+	lastPartial  time.Time
+	silentFrames int
+}
+
+// isSilentFrame reports whether a chunk of samples looks like silence, using
+// a simple RMS energy threshold.
+func isSilentFrame(samples []float32, threshold float32) bool {
+	if len(samples) == 0 {
+		return true
+	}
+	var sum float32
+	for _, s := range samples {
+		sum += s * s
+	}
+	rms := float32(math.Sqrt(float64(sum / float32(len(samples)))))
+	return rms < threshold
+}
+
+// processRollingAudio appends newly received samples to the connection's
+// rolling buffer, emitting a partial result on the configured interval and a
+// final result once the VAD reports sustained silence or the buffer reaches
+// its hard size limit.
+func (s *WhisperService) processRollingAudio(conn *websocket.Conn, state *rollingState, audioData []float32) {
+	state.buffer = append(state.buffer, audioData...)
+
+	if s.config.EnableVAD && isSilentFrame(audioData, s.config.VADThreshold) {
+		state.silentFrames++
+	} else {
+		state.silentFrames = 0
+	}
+
+	maxSamples := int(s.config.RollingWindowSec * float64(s.config.SampleRate))
+	silenceTriggered := s.config.EnableVAD && state.silentFrames >= s.config.SilenceFramesToFinal
+	hardLimitReached := maxSamples > 0 && len(state.buffer) >= maxSamples
+
+	if silenceTriggered || hardLimitReached {
+		if len(state.buffer) > 0 {
+			s.emitRollingResult(conn, state.buffer, true)
+		}
+		state.buffer = nil
+		state.silentFrames = 0
+		state.lastPartial = time.Now()
+		return
+	}
+
+	// While not awake, skip the (comparatively expensive) partial
+	// transcription pass entirely and only pay for it once the buffer
+	// finalizes above, which is also where the wake word gets detected.
+	if !s.isAwake() {
+		return
+	}
+
+	partialInterval := time.Duration(s.config.PartialIntervalMS) * time.Millisecond
+	if len(state.buffer) > 0 && partialInterval > 0 && time.Since(state.lastPartial) >= partialInterval {
+		s.emitRollingResult(conn, state.buffer, false)
+		state.lastPartial = time.Now()
+	}
+}
+
+// forceFinalize immediately transcribes and clears whatever is buffered for
+// this connection, bypassing the silence timer and hard size limit — used by
+// the binary protocol's "flush now" control frame and end-of-utterance frame.
+func (s *WhisperService) forceFinalize(conn *websocket.Conn, state *rollingState) {
+	if len(state.buffer) == 0 {
+		return
+	}
+	s.emitRollingResult(conn, state.buffer, true)
+	state.buffer = nil
+	state.silentFrames = 0
+	state.lastPartial = time.Now()
+}
+
+// emitRollingResult transcribes the given samples and sends a "partial" or
+// "final" message to the originating connection only, so partials from one
+// client never interleave with another client's finals. s.mu is held for
+// the full transcription call, matching transcribeAudio, since concurrent
+// NewContext()/Process() calls against one whisper.Model aren't safe.
+func (s *WhisperService) emitRollingResult(conn *websocket.Conn, samples []float32, isFinal bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.model == nil {
+		return
+	}
+
+	// Copy the buffer: the caller keeps mutating state.buffer after we return.
+	samplesCopy := make([]float32, len(samples))
+	copy(samplesCopy, samples)
+
+	segments, err := s.model.TranscribeSegments(samplesCopy, TranscribeOptions{SingleSegment: !isFinal})
+	if err != nil {
+		log.Printf("[Whisper] rolling transcription error: %v", err)
+		return
+	}
+
+	var text string
+	for _, seg := range segments {
+		text += seg.Text + " "
+	}
+	text = strings.TrimSpace(text)
+
+	var isWakeWord bool
+	var confidence float64
+	if isFinal {
+		isWakeWord, confidence = s.wakeDetector.Detect(text, samplesCopy)
+		if isWakeWord {
+			log.Printf("[Whisper] Wake word detected by %s (confidence %.2f): %s", s.wakeDetector.Name(), confidence, text)
+			s.wake(conn)
+		}
+	}
+
+	msgType := "partial"
+	if isFinal {
+		msgType = "final"
+	}
+
+	result := TranscriptionResult{
+		Text:       text,
+		IsFinal:    isFinal,
+		IsWakeWord: isWakeWord,
+		Confidence: confidence,
+		Detector:   s.wakeDetector.Name(),
+	}
+
+	if err := conn.WriteJSON(map[string]interface{}{
+		"type":   msgType,
+		"result": result,
+	}); err != nil {
+		log.Printf("[Whisper] error sending rolling result to client: %v", err)
+	}
+}
+
 // AddWebSocket adds a new WebSocket connection to broadcast transcriptions to
 func (s *WhisperService) AddWebSocket(conn *websocket.Conn) {
 	s.connMutex.Lock()
@@ -266,10 +502,16 @@ func (s *WhisperService) handleWebSocket(conn *websocket.Conn) {
 		return
 	}
 
-	// Process incoming audio data
+	// Process incoming audio data. Each connection gets its own rolling
+	// buffer so partial hypotheses from one client never interleave with
+	// another client's finals. Clients negotiate the wire format per
+	// message: a gorilla BinaryMessage is parsed with the compact framing
+	// below, while a TextMessage keeps the original JSON []float32 array
+	// for backwards compatibility with older clients.
+	state := &rollingState{lastPartial: time.Now()}
 	messageCount := 0
 	for {
-		_, message, err := conn.ReadMessage()
+		mt, message, err := conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 				log.Printf("[Whisper] websocket error: %v", err)
@@ -280,11 +522,42 @@ func (s *WhisperService) handleWebSocket(conn *websocket.Conn) {
 		}
 
 		if messageCount == 0 {
-			log.Printf("[Whisper] Received first audio message, size: %d bytes", len(message))
+			log.Printf("[Whisper] Received first audio message, size: %d bytes, binary: %v", len(message), mt == websocket.BinaryMessage)
 		}
 		messageCount++
 
-		// Handle audio data
+		if mt == websocket.BinaryMessage {
+			if len(message) == 0 {
+				continue
+			}
+			switch message[0] {
+			case wsFrameAudio:
+				audioData, err := decodeBinaryAudioFrame(message[1:])
+				if err != nil {
+					log.Printf("[Whisper] error decoding binary audio frame (message %d): %v", messageCount, err)
+					continue
+				}
+				s.processRollingAudio(conn, state, audioData)
+			case wsFrameControl:
+				if len(message) < 2 {
+					log.Printf("[Whisper] control frame missing opcode")
+					continue
+				}
+				switch message[1] {
+				case wsControlFlush:
+					s.forceFinalize(conn, state)
+				default:
+					log.Printf("[Whisper] unknown control opcode: 0x%02x", message[1])
+				}
+			case wsFrameEndOfUtterance:
+				s.forceFinalize(conn, state)
+			default:
+				log.Printf("[Whisper] unknown binary frame type: 0x%02x", message[0])
+			}
+			continue
+		}
+
+		// Legacy JSON audio data
 		var audioData []float32
 		if err := json.Unmarshal(message, &audioData); err != nil {
 			log.Printf("[Whisper] error unmarshaling audio data (message %d): %v, first 100 bytes: %s", messageCount, err, string(message[:min(100, len(message))]))
@@ -295,10 +568,7 @@ func (s *WhisperService) handleWebSocket(conn *websocket.Conn) {
 			log.Printf("[Whisper] Successfully unmarshaled first audio chunk, length: %d samples", len(audioData))
 		}
 
-		// Process the audio
-		if err := s.ProcessAudio(audioData); err != nil {
-			log.Printf("[Whisper] error processing audio: %v", err)
-		}
+		s.processRollingAudio(conn, state, audioData)
 	}
 }
 
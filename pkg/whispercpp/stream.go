@@ -0,0 +1,191 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package whispercpp
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+)
+
+// Result is one interim or final hypothesis from Stream.
+type Result struct {
+	Text       string  `json:"text"`
+	IsFinal    bool    `json:"isFinal"`
+	StartMS    int     `json:"startMs"`
+	EndMS      int     `json:"endMs"`
+	Confidence float64 `json:"confidence,omitempty"`
+}
+
+// preWakeRingMS is how much audio Stream keeps buffered before the wake
+// word fires, so the first words spoken right after "alfred" aren't
+// clipped once transcription is promoted to full.
+const preWakeRingMS = 500
+
+// Stream starts a streaming recognizer analogous to Google's
+// StreamingRecognize: send 16kHz mono float32 PCM frames on the returned
+// channel, and read interim/final Results from the second. While idle,
+// only a lightweight VAD+keyword check runs per frame; once Config.WakeWord
+// fires, Stream promotes to full transcription until sustained silence
+// ends the utterance. The output channel closes once ctx is done or the
+// caller closes the input channel; the input channel is never closed here. WebSocketStreamHandler is the HTTP-facing
+// caller of this API.
+func (s *WhisperService) Stream(ctx context.Context) (chan<- []float32, <-chan Result) {
+	in := make(chan []float32, 16)
+	out := make(chan Result, 16)
+
+	go s.runStream(ctx, in, out)
+
+	return in, out
+}
+
+func (s *WhisperService) runStream(ctx context.Context, in <-chan []float32, out chan<- Result) {
+	defer close(out)
+
+	ringMaxSamples := preWakeRingMS * s.config.SampleRate / 1000
+
+	var (
+		ring          []float32 // pre-wake audio, so the wake word's tail isn't clipped
+		buffer        []float32 // in-utterance audio, once awake
+		bufferStartMS int
+		silentFrames  int
+		awake         bool
+		elapsedMS     int
+	)
+	lastPartial := time.Now()
+
+	finalize := func() {
+		if len(buffer) == 0 {
+			return
+		}
+		s.emitStreamResult(out, buffer, bufferStartMS, elapsedMS, true)
+		buffer = nil
+		awake = false
+		silentFrames = 0
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			finalize()
+			return
+
+		case frame, ok := <-in:
+			if !ok {
+				finalize()
+				return
+			}
+
+			frameMS := len(frame) * 1000 / s.config.SampleRate
+			silent := s.config.EnableVAD && isSilentFrame(frame, s.config.VADThreshold)
+
+			if !awake {
+				ring = append(ring, frame...)
+				if ringMaxSamples > 0 && len(ring) > ringMaxSamples {
+					ring = ring[len(ring)-ringMaxSamples:]
+				}
+
+				if !silent && s.detectWakeInRing(ring) {
+					awake = true
+					buffer = append(buffer, ring...)
+					ring = nil
+					bufferStartMS = elapsedMS - len(buffer)*1000/s.config.SampleRate
+					silentFrames = 0
+					lastPartial = time.Now()
+				}
+			} else {
+				buffer = append(buffer, frame...)
+				if silent {
+					silentFrames++
+				} else {
+					silentFrames = 0
+				}
+
+				if s.config.EnableVAD && silentFrames >= s.config.SilenceFramesToFinal {
+					finalize()
+				} else {
+					partialInterval := time.Duration(s.config.PartialIntervalMS) * time.Millisecond
+					if partialInterval > 0 && time.Since(lastPartial) >= partialInterval {
+						s.emitStreamResult(out, buffer, bufferStartMS, elapsedMS, false)
+						lastPartial = time.Now()
+					}
+				}
+			}
+
+			elapsedMS += frameMS
+		}
+	}
+}
+
+// detectWakeInRing runs a quick single-segment transcription over the
+// pre-wake ring buffer and checks it against the configured wake word, so
+// idle audio only pays for a short transcription pass rather than a full
+// one on every frame. s.mu is held for the whole transcription call, like
+// transcribeAudio and emitRollingResult, since concurrent NewContext()/
+// Process() calls against one whisper.Model aren't safe.
+func (s *WhisperService) detectWakeInRing(ring []float32) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.model == nil {
+		return false
+	}
+
+	segments, err := s.model.TranscribeSegments(ring, TranscribeOptions{SingleSegment: true})
+	if err != nil {
+		return false
+	}
+
+	text := joinSegments(segments)
+	detected, _ := s.wakeDetector.Detect(text, ring)
+	return detected
+}
+
+// emitStreamResult transcribes samples and sends a Result on out, tagging
+// it final or interim. Confidence is only populated for finals where the
+// wake word was just re-confirmed in the segment; whisper.cpp's bindings
+// don't expose a per-segment probability to use more generally. s.mu is
+// held for the whole transcription call; see detectWakeInRing.
+func (s *WhisperService) emitStreamResult(out chan<- Result, samples []float32, startMS, endMS int, isFinal bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.model == nil {
+		return
+	}
+
+	samplesCopy := make([]float32, len(samples))
+	copy(samplesCopy, samples)
+
+	segments, err := s.model.TranscribeSegments(samplesCopy, TranscribeOptions{SingleSegment: !isFinal})
+	if err != nil {
+		log.Printf("[Whisper] stream transcription error: %v", err)
+		return
+	}
+
+	text := joinSegments(segments)
+
+	var confidence float64
+	if isFinal {
+		_, confidence = s.wakeDetector.Detect(text, samplesCopy)
+	}
+
+	out <- Result{
+		Text:       text,
+		IsFinal:    isFinal,
+		StartMS:    startMS,
+		EndMS:      endMS,
+		Confidence: confidence,
+	}
+}
+
+// joinSegments concatenates segment text into a single trimmed string.
+func joinSegments(segments []Segment) string {
+	var text string
+	for _, seg := range segments {
+		text += seg.Text + " "
+	}
+	return strings.TrimSpace(text)
+}
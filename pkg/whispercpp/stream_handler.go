@@ -0,0 +1,78 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package whispercpp
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// WebSocketStreamHandler handles /api/whisper/stream, a thin WebSocket
+// adapter over Stream: inbound binary audio frames (the same wsFrameAudio
+// framing as WebSocketHandler) are decoded and pushed onto Stream's input
+// channel, and each Result read back from Stream's output channel is
+// written to the client as a JSON text message.
+func WebSocketStreamHandler(w http.ResponseWriter, r *http.Request) {
+	service := GetWhisperService()
+	if service == nil {
+		log.Printf("[Whisper] ERROR: Service not initialized")
+		http.Error(w, "Whisper service not initialized", http.StatusServiceUnavailable)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Printf("[Whisper] ERROR: stream WebSocket upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		conn.Close() // unblocks a ReadMessage call still waiting on the client
+	}()
+
+	in, out := service.Stream(ctx)
+
+	resultsDone := make(chan struct{})
+	go func() {
+		defer close(resultsDone)
+		for result := range out {
+			if err := conn.WriteJSON(result); err != nil {
+				log.Printf("[Whisper] error writing stream result: %v", err)
+				cancel()
+				return
+			}
+		}
+	}()
+
+	for {
+		mt, message, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		if mt != websocket.BinaryMessage || len(message) == 0 || message[0] != wsFrameAudio {
+			continue
+		}
+
+		audioData, err := decodeBinaryAudioFrame(message[1:])
+		if err != nil {
+			log.Printf("[Whisper] error decoding stream audio frame: %v", err)
+			continue
+		}
+
+		select {
+		case in <- audioData:
+		case <-ctx.Done():
+		}
+	}
+
+	close(in)
+	<-resultsDone
+}
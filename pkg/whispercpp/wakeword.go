@@ -0,0 +1,138 @@
+// Copyright 2025, Command Line Inc.
+// SPDX-License-Identifier: Apache-2.0
+
+package whispercpp
+
+import (
+	"strings"
+)
+
+// WakeWordDetector decides whether Alfred's wake word was heard. Detect is
+// given both the finalized transcript and the underlying samples so that
+// text-based and audio-based implementations can share one interface.
+type WakeWordDetector interface {
+	// Name identifies the detector implementation; surfaced on
+	// TranscriptionResult so clients/logs know which one fired.
+	Name() string
+	// Detect reports whether the wake word was heard, along with a
+	// confidence score in [0, 1].
+	Detect(text string, samples []float32) (bool, float64)
+}
+
+// SubstringDetector is the original substring-match behavior, generalized
+// to a configurable phrase list with fuzzy matching (edit distance <= 2) so
+// whisper mis-hearings like "hey alford" still trigger.
+type SubstringDetector struct {
+	Phrases []string
+}
+
+// NewSubstringDetector builds a SubstringDetector over the given wake
+// phrases (case-insensitive).
+func NewSubstringDetector(phrases ...string) *SubstringDetector {
+	return &SubstringDetector{Phrases: phrases}
+}
+
+func (d *SubstringDetector) Name() string { return "substring" }
+
+// Detect looks for an exact or near (edit distance <= 2) match of any
+// configured phrase in text. It never inspects samples.
+func (d *SubstringDetector) Detect(text string, _ []float32) (bool, float64) {
+	lower := strings.ToLower(strings.TrimSpace(text))
+	if lower == "" {
+		return false, 0
+	}
+
+	for _, phrase := range d.Phrases {
+		phrase = strings.ToLower(phrase)
+		if strings.Contains(lower, phrase) {
+			return true, 1.0
+		}
+		if dist := fuzzyContains(lower, phrase); dist <= 2 {
+			confidence := 1.0 - float64(dist)/float64(len(phrase)+1)
+			return true, confidence
+		}
+	}
+	return false, 0
+}
+
+// fuzzyContains slides a window roughly the length of phrase over text and
+// returns the smallest Levenshtein distance found, so a mis-transcribed
+// wake word ("hey alford") still matches "hey alfred".
+func fuzzyContains(text, phrase string) int {
+	best := len(phrase) + 1
+	n := len(phrase)
+	for start := 0; start < len(text); start++ {
+		for length := n - 2; length <= n+2; length++ {
+			end := start + length
+			if length < 1 || end > len(text) {
+				continue
+			}
+			if dist := levenshtein(text[start:end], phrase); dist < best {
+				best = dist
+			}
+		}
+	}
+	return best
+}
+
+// levenshtein computes the classic edit distance between a and b.
+func levenshtein(a, b string) int {
+	if a == b {
+		return 0
+	}
+	rows, cols := len(a)+1, len(b)+1
+	prev := make([]int, cols)
+	curr := make([]int, cols)
+	for j := 0; j < cols; j++ {
+		prev[j] = j
+	}
+	for i := 1; i < rows; i++ {
+		curr[0] = i
+		for j := 1; j < cols; j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(curr[j-1]+1, prev[j]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[cols-1]
+}
+
+func min3(a, b, c int) int {
+	m := a
+	if b < m {
+		m = b
+	}
+	if c < m {
+		m = c
+	}
+	return m
+}
+
+// KeywordSpottingDetector is the extension point for an always-on model
+// (e.g. an openWakeWord ONNX model or a tiny whisper.cpp tiny.en context)
+// that runs independently of the main transcription path, so listening for
+// the wake word doesn't cost a full whisper pass on every frame.
+//
+// This is currently a stub: without a bundled keyword-spotting model it
+// never fires, so wiring it in is a safe no-op rather than a false trigger.
+// Swapping in a real model only requires implementing Detect below.
+type KeywordSpottingDetector struct {
+	ModelPath string
+	Threshold float64
+}
+
+// NewKeywordSpottingDetector configures a keyword-spotting detector against
+// the given model and confidence threshold.
+func NewKeywordSpottingDetector(modelPath string, threshold float64) *KeywordSpottingDetector {
+	return &KeywordSpottingDetector{ModelPath: modelPath, Threshold: threshold}
+}
+
+func (d *KeywordSpottingDetector) Name() string { return "keyword-spotting" }
+
+// Detect is not yet wired to a real keyword-spotting model.
+func (d *KeywordSpottingDetector) Detect(_ string, _ []float32) (bool, float64) {
+	return false, 0
+}